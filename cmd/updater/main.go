@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"log"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -38,7 +37,14 @@ func main() {
 	}
 	defer mongoClient.Disconnect(ctx)
 
-	apiClient := client.NewFootballAPIClient(&http.Client{}, cfg.FootballDataAPIKey)
+	footballData, err := client.NewFootballData(cfg.FootballDataAPIKey, cfg.FootballDataReplayDir, cfg.FootballDataRecordDir, client.NewRateLimiter(cfg.UpdaterRequestsPerMinute))
+	if err != nil {
+		log.Fatalf("Failed to create football-data client: %v", err)
+	}
+	apiClient, err := client.LoadRegistry(cfg.ProvidersPath, footballData)
+	if err != nil {
+		log.Fatalf("Failed to load data providers: %v", err)
+	}
 	redisClient, err := cache.NewRedisClient(cfg.RedisURL)
 	if err != nil {
 		log.Fatalf("Failed to connect to Redis: %v", err)
@@ -48,30 +54,66 @@ func main() {
 	matchesStore := mongodb.NewMongoDBMatchesStore(mongoClient, "football", "matches")
 	standingsStore := mongodb.NewMongoDBStandingsStore(mongoClient, "football")
 	teamsStore := mongodb.NewMongoDBTeamsStore(mongoClient, "football")
+	eloStore := mongodb.NewMongoDBEloStore(mongoClient, "football")
+	rivalryStore := mongodb.NewMongoDBRivalryStore(mongoClient, "football")
+	historyStore := mongodb.NewMongoDBRatingHistoryStore(mongoClient, "football")
+	seasonStore := mongodb.NewMongoDBSeasonStore(mongoClient, "football")
+
+	ratingProfiles, err := service.NewRatingProfiles(cfg.RatingProfilePath)
+	if err != nil {
+		log.Fatalf("Failed to load rating profile: %v", err)
+	}
+	profile := ratingProfiles.Current()
+	log.Printf("Using rating profile %s v%d (%s)", profile.Name, profile.Version, profile.Checksum())
+
+	strategies := service.NewRatingStrategies(
+		service.NewHeuristicStrategy(ratingProfiles),
+		service.NewBalanceStrategy(ratingProfiles),
+		service.NewEloStrategy(ratingProfiles),
+	)
+	strategy, err := strategies.Get(cfg.RatingStrategy)
+	if err != nil {
+		log.Fatalf("Failed to select rating strategy: %v", err)
+	}
+	log.Printf("Using rating strategy %s v%s", strategy.Name(), strategy.Version())
 
-	matchesService := service.NewMatchesService(matchesStore, apiClient)
+	matchesService := service.NewMatchesService(matchesStore, historyStore, apiClient, strategy)
 	standingsService := service.NewStandingService(standingsStore)
+	seasonService := service.NewSeasonService(seasonStore, apiClient)
 	teamsService := service.NewTeamsService(teamsStore)
-	calculator := service.NewCalculatorAdapter(teamsStore, standingsStore, matchesStore)
+	eloService := service.NewEloService(eloStore, matchesStore)
+	statsService := service.NewStatsService(matchesStore, teamsStore)
+	calculator := service.NewCalculatorAdapter(teamsStore, standingsStore, matchesStore, eloStore, rivalryStore, statsService)
 
-	// Создаем планировщик
+	// Создаем планировщик; задачи выполняются по одной, а совпавшие по времени ждут очереди, а не пропускаются
 	scheduler := gocron.NewScheduler(time.UTC)
-	scheduler.SetMaxConcurrentJobs(1, gocron.RescheduleMode)
+	scheduler.SetMaxConcurrentJobs(1, gocron.WaitMode)
 
 	// Регистрируем задачи
-	jobs.RegisterStandingsJob(scheduler, standingsService, redisClient, apiClient)
+	jobs.RegisterSeasonsJob(scheduler, seasonService)
+	jobs.RegisterStandingsJob(scheduler, standingsService, matchesService, seasonService, redisClient, apiClient, calculator)
 	jobs.RegisterTeamsJob(scheduler, teamsService, apiClient)
+	jobs.RegisterEloJob(scheduler, eloService)
 	jobs.RegisterMatchesJob(scheduler, matchesService, redisClient, apiClient, calculator)
 
-	// Запускаем планировщик
+	// Сезоны загружаются до первого обновления таблиц, иначе на пустой базе год сезона угадывается по дате
+	jobs.RefreshSeasons(ctx, seasonService)
+
+	// Запускаем планировщик и слежение за профилем рейтинга
 	scheduler.StartAsync()
+	go jobs.WatchRatingProfile(ctx, ratingProfiles, time.Minute)
 
 	log.Println("Data updater service started")
 
-	// Ожидание сигналов завершения
+	// Ожидание сигналов завершения; SIGHUP перечитывает профиль рейтинга
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range sigChan {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		jobs.ReloadRatingProfile(ratingProfiles)
+	}
 
 	log.Println("Shutting down data updater...")
 	scheduler.Stop()
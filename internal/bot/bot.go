@@ -4,17 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
+	"time"
 
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/failure"
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/fsm"
 	"github.com/vsespontanno/tgbot_fschedule/internal/bot/handlers"
 	"github.com/vsespontanno/tgbot_fschedule/internal/cache"
 	"github.com/vsespontanno/tgbot_fschedule/internal/client"
 	"github.com/vsespontanno/tgbot_fschedule/internal/config"
 	"github.com/vsespontanno/tgbot_fschedule/internal/db"
+	"github.com/vsespontanno/tgbot_fschedule/internal/jobs"
 	mongoRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
 	pgRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/postgres"
 	"github.com/vsespontanno/tgbot_fschedule/internal/service"
 
+	"github.com/go-co-op/gocron"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -53,34 +57,122 @@ func Start() error {
 	// Initialize stores and services
 	matchesStore := mongoRepo.NewMongoDBMatchesStore(mongoClient, "football", "matches")
 	standingsStore := mongoRepo.NewMongoDBStandingsStore(mongoClient, "football")
+	eloStore := mongoRepo.NewMongoDBEloStore(mongoClient, "football")
+	teamsStore := mongoRepo.NewMongoDBTeamsStore(mongoClient, "football")
+	rivalryStore := mongoRepo.NewMongoDBRivalryStore(mongoClient, "football")
+	historyStore := mongoRepo.NewMongoDBRatingHistoryStore(mongoClient, "football")
 	userStore := pgRepo.NewPGUserStore(pg)
+	eventStore := pgRepo.NewPGEventStore(pg)
+	prefStore := pgRepo.NewPGPreferenceStore(pg)
+	upsetAlertStore := pgRepo.NewPGUpsetAlertStore(pg)
 
-	footballData := client.NewFootballAPIClient(&http.Client{}, cfg.FootballDataAPIKey)
+	footballData, err := client.NewFootballData(cfg.FootballDataAPIKey, cfg.FootballDataReplayDir, cfg.FootballDataRecordDir, client.NewRateLimiter(cfg.BotRequestsPerMinute))
+	if err != nil {
+		return fmt.Errorf("failed to create football-data client: %w", err)
+	}
+	providers, err := client.LoadRegistry(cfg.ProvidersPath, footballData)
+	if err != nil {
+		return fmt.Errorf("failed to load data providers: %w", err)
+	}
 
 	standingsService := service.NewStandingService(standingsStore)
-	matchesService := service.NewMatchesService(matchesStore, footballData)
-	userService := service.NewUserService(userStore)
+	ratingProfiles, err := service.NewRatingProfiles(cfg.RatingProfilePath)
+	if err != nil {
+		return fmt.Errorf("failed to load rating profile: %w", err)
+	}
+	strategies := service.NewRatingStrategies(
+		service.NewHeuristicStrategy(ratingProfiles),
+		service.NewBalanceStrategy(ratingProfiles),
+		service.NewEloStrategy(ratingProfiles),
+	)
+	strategy, err := strategies.Get(cfg.RatingStrategy)
+	if err != nil {
+		return fmt.Errorf("failed to select rating strategy: %w", err)
+	}
+	matchesService := service.NewMatchesService(matchesStore, historyStore, providers, strategy)
+	// Топы строятся только по рейтингам текущей версии профиля, поэтому бот
+	// подхватывает новый профиль вслед за сервисом обновления
+	profileCtx, stopProfileWatch := context.WithCancel(context.Background())
+	defer stopProfileWatch()
+	go jobs.WatchRatingProfile(profileCtx, ratingProfiles, time.Minute)
+	eloService := service.NewEloService(eloStore, matchesStore)
+	rivalryService := service.NewRivalryService(rivalryStore, teamsStore)
+	statsService := service.NewStatsService(matchesStore, teamsStore)
+	upsetService := service.NewUpsetService(matchesStore, standingsStore, upsetAlertStore, prefStore)
+	personalService := service.NewPersonalizationService(matchesService, prefStore, eventStore, teamsStore, redisClient)
+	// Состояния многошаговых диалогов хранятся в Redis
+	dialogs := fsm.NewMachine(redisClient)
+	if err := handlers.RegisterRivalryDialog(dialogs, rivalryService, cfg.IsAdmin); err != nil {
+		return fmt.Errorf("failed to register rivalry dialog: %w", err)
+	}
+	analyticsService := service.NewAnalyticsService(eventStore)
+	defer analyticsService.Close()
+	// События удаляются через сервис аналитики, чтобы не осталось ещё не записанных
+	userService := service.NewUserService(userStore, analyticsService, personalService, prefStore, dialogs)
+
+	// Уведомления о сенсациях рассылаются в фоне
+	scheduler := gocron.NewScheduler(time.UTC)
+	jobs.RegisterUpsetAlertsJob(scheduler, upsetService, bot)
+	scheduler.StartAsync()
+	defer scheduler.Stop()
 
-	return handleUpdates(bot, standingsService, matchesService, userService, redisClient)
+	return handleUpdates(bot, cfg, standingsService, matchesService, userService, analyticsService, eloService, rivalryService, personalService, statsService, upsetService, redisClient, dialogs)
 }
 
-func handleUpdates(bot *tgbotapi.BotAPI, standingsService *service.StandingsService, matchesService *service.MatchesService, userService *service.UserService, redisClient *cache.RedisClient) error {
+func handleUpdates(bot *tgbotapi.BotAPI, cfg *config.Config, standingsService *service.StandingsService, matchesService *service.MatchesService, userService *service.UserService, analyticsService *service.AnalyticsService, eloService *service.EloService, rivalryService *service.RivalryService, personalService *service.PersonalizationService, statsService *service.StatsService, upsetService *service.UpsetService, redisClient *cache.RedisClient, dialogs *fsm.Machine) error {
+	reporter := failure.NewReporter(bot, cfg.AdminChatID)
 	updateConfig := tgbotapi.NewUpdate(0)
 	updateConfig.Timeout = 60
 	updates := bot.GetUpdatesChan(updateConfig)
 
 	for update := range updates {
 		if update.Message != nil {
-			if err := handlers.HandleMessage(bot, update.Message, userService); err != nil {
-				log.Printf("Error handling message: %v", err)
-			}
+			msg := update.Message
+			start := time.Now()
+			err := safeHandle(func() error {
+				return handlers.HandleMessage(bot, msg, cfg, userService, analyticsService, eloService, rivalryService, personalService, statsService, upsetService, dialogs)
+			})
+			reporter.Handle(msg.Chat.ID, messageSource(msg), err)
+			analyticsService.Track(messageEvent(msg, time.Since(start), err))
 		}
 
 		if update.CallbackQuery != nil {
-			if err := handlers.HandleCallbackQuery(bot, update.CallbackQuery, matchesService, standingsService, redisClient); err != nil {
-				log.Printf("Error handling callback query: %v", err)
+			query := update.CallbackQuery
+			start := time.Now()
+			err := safeHandle(func() error {
+				return handlers.HandleCallbackQuery(bot, query, matchesService, standingsService, userService, personalService, redisClient)
+			})
+			var chatID int64
+			if query.Message != nil {
+				chatID = query.Message.Chat.ID
+			}
+			reporter.Handle(chatID, "callback "+query.Data, err)
+			if event, ok := callbackEvent(query, time.Since(start), err); ok {
+				analyticsService.Track(event)
 			}
 		}
+
+		if update.InlineQuery != nil {
+			analyticsService.Track(inlineQueryEvent(update.InlineQuery))
+		}
 	}
 	return nil
 }
+
+// Вызывает обработчик и превращает панику в ошибку, чтобы один апдейт не ронял бота
+func safeHandle(handle func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = failure.Panic(v)
+		}
+	}()
+	return handle()
+}
+
+// Описание источника ошибки для сообщения
+func messageSource(msg *tgbotapi.Message) string {
+	if msg.IsCommand() {
+		return "command /" + msg.Command()
+	}
+	return "message"
+}
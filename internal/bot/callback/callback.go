@@ -0,0 +1,109 @@
+package callback
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Версия формата callback data.
+// Увеличивается при любом несовместимом изменении действий или их параметров,
+// тогда кнопки из старых сообщений получают ответ "откройте меню заново"
+const Version byte = '1'
+
+// Telegram ограничивает callback data 64 байтами
+const MaxLength = 64
+
+// Разделитель действия и параметров
+const separator = ":"
+
+// Action - короткий код действия кнопки
+type Action string
+
+const (
+	ActionStandings       Action = "st"
+	ActionSchedule        Action = "sc"
+	ActionTopMatches      Action = "tm"
+	ActionAllMatches      Action = "am"
+	ActionDeleteMeConfirm Action = "dy"
+	ActionDeleteMeCancel  Action = "dn"
+	ActionExplainTop      Action = "ex"
+)
+
+var (
+	// ErrStale возвращается для кнопок другой версии или старого формата без версии
+	ErrStale = errors.New("stale callback data")
+	// ErrTooLong возвращается, если закодированные данные не помещаются в лимит Telegram
+	ErrTooLong = errors.New("callback data too long")
+	// ErrMalformed возвращается для данных, которые не удалось разобрать
+	ErrMalformed = errors.New("malformed callback data")
+)
+
+// Payload - разобранные данные кнопки: действие и позиционные параметры
+type Payload struct {
+	Action Action
+	Params []string
+}
+
+// Param возвращает i-й параметр или пустую строку, если его нет
+func (p Payload) Param(i int) string {
+	if i < 0 || i >= len(p.Params) {
+		return ""
+	}
+	return p.Params[i]
+}
+
+// Encode кодирует действие и параметры в строку вида "1st:EPL"
+// Первый байт - версия формата
+func Encode(action Action, params ...string) (string, error) {
+	if action == "" || strings.Contains(string(action), separator) {
+		return "", fmt.Errorf("%w: bad action %q", ErrMalformed, action)
+	}
+	var b strings.Builder
+	b.WriteByte(Version)
+	b.WriteString(string(action))
+	for _, p := range params {
+		if strings.Contains(p, separator) {
+			return "", fmt.Errorf("%w: parameter %q contains %q", ErrMalformed, p, separator)
+		}
+		b.WriteString(separator)
+		b.WriteString(p)
+	}
+	if b.Len() > MaxLength {
+		return "", fmt.Errorf("%w: %d bytes", ErrTooLong, b.Len())
+	}
+	return b.String(), nil
+}
+
+// MustEncode как Encode, но паникует при ошибке
+// Используется для статических клавиатур, которые собираются при старте
+func MustEncode(action Action, params ...string) string {
+	data, err := Encode(action, params...)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// Decode разбирает callback data
+// Для данных другой версии или старого формата возвращает ErrStale
+func Decode(data string) (Payload, error) {
+	if data == "" {
+		return Payload{}, ErrMalformed
+	}
+	if data[0] != Version {
+		return Payload{}, ErrStale
+	}
+	parts := strings.Split(data[1:], separator)
+	if parts[0] == "" {
+		return Payload{}, ErrMalformed
+	}
+	return Payload{Action: Action(parts[0]), Params: parts[1:]}, nil
+}
+
+// Button создаёт инлайн-кнопку с закодированными данными
+func Button(text string, action Action, params ...string) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(text, MustEncode(action, params...))
+}
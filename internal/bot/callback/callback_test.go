@@ -0,0 +1,42 @@
+package callback
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	data, err := Encode(ActionSchedule, "UCL")
+	if err != nil {
+		t.Fatalf("Encode returned an error: %v", err)
+	}
+	if data != "1sc:UCL" {
+		t.Errorf("Encode returned %q, want %q", data, "1sc:UCL")
+	}
+
+	payload, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode returned an error: %v", err)
+	}
+	if payload.Action != ActionSchedule || payload.Param(0) != "UCL" || payload.Param(1) != "" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestDecodeStale(t *testing.T) {
+	for _, data := range []string{"standings_EPL", "schedule_UCL", "show_top_matches", "0st:EPL"} {
+		if _, err := Decode(data); !errors.Is(err, ErrStale) {
+			t.Errorf("Decode(%q) = %v, want ErrStale", data, err)
+		}
+	}
+}
+
+func TestEncodeLimits(t *testing.T) {
+	if _, err := Encode(ActionSchedule, strings.Repeat("x", MaxLength)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+	if _, err := Encode(ActionSchedule, "a:b"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("expected ErrMalformed, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package bot
+
+import (
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/callback"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Команды, которые обрабатывает handlers.HandleMessage; остальные записываются как unknownCommandEvent
+var knownCommands = map[string]bool{
+	"start": true, "help": true, "schedule": true, "table": true, "elo": true, "match": true,
+	"shocks": true, "follow": true, "hide": true, "unfollow": true, "prefs": true,
+	"export_me": true, "delete_me": true, "cancel": true,
+	"stats": true, "rivalries": true, "rivalry_add": true, "rivalry_edit": true,
+}
+
+// Имя события для неизвестной команды: пользователь может прислать команду любой длины,
+// а имя события должно помещаться в колонку events.name
+const unknownCommandEvent = "unknown_command"
+
+// Собирает событие аналитики для входящего сообщения
+// Обычный текст (например, ответ в диалоге) записывается под именем "text"
+func messageEvent(msg *tgbotapi.Message, latency time.Duration, err error) types.Event {
+	name := "text"
+	if msg.IsCommand() {
+		name = unknownCommandEvent
+		if command := msg.Command(); knownCommands[command] {
+			name = "/" + command
+		}
+	}
+	return types.Event{
+		TelegramID: msg.Chat.ID,
+		ChatType:   msg.Chat.Type,
+		Kind:       types.EventCommand,
+		Name:       name,
+		LatencyMs:  latency.Milliseconds(),
+		Outcome:    outcome(err),
+	}
+}
+
+// Собирает событие аналитики для нажатия кнопки
+// Для кнопок таблиц и расписаний параметр кнопки сохраняется как лига
+// Подтверждение /delete_me не записывается, иначе после удаления данных осталось бы событие
+func callbackEvent(query *tgbotapi.CallbackQuery, latency time.Duration, err error) (types.Event, bool) {
+	event := types.Event{
+		TelegramID: query.From.ID,
+		Kind:       types.EventCallback,
+		Name:       "stale",
+		LatencyMs:  latency.Milliseconds(),
+		Outcome:    outcome(err),
+	}
+	if query.Message != nil {
+		event.TelegramID = query.Message.Chat.ID
+		event.ChatType = query.Message.Chat.Type
+	}
+	if payload, decodeErr := callback.Decode(query.Data); decodeErr == nil {
+		event.Name = string(payload.Action)
+		switch payload.Action {
+		case callback.ActionStandings, callback.ActionSchedule:
+			event.League = payload.Param(0)
+		case callback.ActionDeleteMeConfirm:
+			return event, false
+		}
+	}
+	return event, true
+}
+
+// Собирает событие аналитики для инлайн-запроса
+// Бот пока не отвечает на инлайн-запросы, поэтому исход всегда "unhandled"
+func inlineQueryEvent(query *tgbotapi.InlineQuery) types.Event {
+	return types.Event{
+		TelegramID: query.From.ID,
+		ChatType:   query.ChatType,
+		Kind:       types.EventInlineQuery,
+		Name:       "inline",
+		Outcome:    types.OutcomeUnhandled,
+	}
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return types.OutcomeError
+	}
+	return types.OutcomeOK
+}
@@ -0,0 +1,38 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func commandMessage(text string) *tgbotapi.Message {
+	command := strings.Fields(text)[0]
+	return &tgbotapi.Message{
+		Text:     text,
+		Chat:     &tgbotapi.Chat{ID: 1, Type: "private"},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}},
+	}
+}
+
+func TestMessageEventName(t *testing.T) {
+	cases := map[string]string{
+		"/follow Arsenal":              "/follow",
+		"/delete_me":                   "/delete_me",
+		"/nosuchcommand":               unknownCommandEvent,
+		"/" + strings.Repeat("a", 200): unknownCommandEvent,
+	}
+	for text, want := range cases {
+		event := messageEvent(commandMessage(text), 0, nil)
+		if event.Name != want {
+			t.Errorf("%.20q: name = %q, want %q", text, event.Name, want)
+		}
+		if len(event.Name) > 64 {
+			t.Errorf("%.20q: name %q does not fit events.name", text, event.Name)
+		}
+	}
+	if event := messageEvent(&tgbotapi.Message{Text: "Arsenal", Chat: &tgbotapi.Chat{ID: 1}}, 0, nil); event.Name != "text" {
+		t.Errorf("plain text name = %q, want text", event.Name)
+	}
+}
@@ -0,0 +1,88 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Сообщение пользователю, если обработчик вернул ошибку без собственного текста
+const DefaultUserMessage = "Что-то пошло не так. Попробуйте позже."
+
+// Error - ошибка обработчика с классификацией
+// UserFacing-ошибки ожидаемы (неверный ввод, нет данных) и не отправляются администраторам,
+// внутренние ошибки (БД, API, рендеринг) показываются пользователю общим текстом и репортятся
+type Error struct {
+	// Текст, который увидит пользователь
+	Message    string
+	UserFacing bool
+	Err        error
+	// Стек в момент создания ошибки, только для внутренних
+	stack []uintptr
+	// Готовый стек для паник, снятый через debug.Stack
+	panicStack string
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// User создаёт ожидаемую ошибку: пользователь увидит message, администраторам ничего не уйдёт
+func User(message string) error {
+	return &Error{Message: message, UserFacing: true}
+}
+
+// Internal создаёт внутреннюю ошибку с текстом для пользователя и стеком вызова
+// Пустой message заменяется на DefaultUserMessage
+func Internal(message string, err error) error {
+	if message == "" {
+		message = DefaultUserMessage
+	}
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &Error{Message: message, Err: err, stack: pcs[:n]}
+}
+
+// Panic превращает значение из recover() во внутреннюю ошибку со стеком паники
+func Panic(v interface{}) error {
+	return &Error{Message: DefaultUserMessage, Err: fmt.Errorf("panic: %v", v), panicStack: string(debug.Stack())}
+}
+
+// Classify приводит любую ошибку к *Error
+// Обычные ошибки без классификации считаются внутренними
+func Classify(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return &Error{Message: DefaultUserMessage, Err: err}
+}
+
+// Stack возвращает стек вызова в текстовом виде или пустую строку, если он не сохранён
+func (e *Error) Stack() string {
+	if e.panicStack != "" {
+		return e.panicStack
+	}
+	if len(e.stack) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
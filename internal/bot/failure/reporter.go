@@ -0,0 +1,165 @@
+package failure
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// Одинаковые ошибки репортятся не чаще раза за окно, остальные только считаются
+	dedupWindow = 10 * time.Minute
+	// Не больше rateLimit отчётов в админ-чат за rateWindow, чтобы не упереться в лимиты Telegram
+	rateLimit  = 5
+	rateWindow = time.Minute
+	// Telegram режет сообщения длиннее 4096 символов
+	maxReportLength = 3500
+)
+
+// Числа (ID матчей, команд, чатов) убираются из отпечатка, чтобы одна и та же
+// ошибка для разных объектов считалась дубликатом
+var digits = regexp.MustCompile(`\d+`)
+
+// Информация о последнем отчёте по отпечатку ошибки
+type occurrence struct {
+	reportedAt time.Time
+	suppressed int
+}
+
+// Reporter отвечает пользователю на ошибку обработчика и отправляет внутренние ошибки
+// в админ-чат с дедупликацией и ограничением частоты
+type Reporter struct {
+	bot         *tgbotapi.BotAPI
+	adminChatID int64
+
+	mu   sync.Mutex
+	seen map[string]*occurrence
+	sent []time.Time
+	now  func() time.Time
+}
+
+// Конструктор для создания Reporter
+// Если adminChatID равен 0, внутренние ошибки только пишутся в лог
+func NewReporter(bot *tgbotapi.BotAPI, adminChatID int64) *Reporter {
+	return &Reporter{
+		bot:         bot,
+		adminChatID: adminChatID,
+		seen:        make(map[string]*occurrence),
+		now:         time.Now,
+	}
+}
+
+// Handle обрабатывает ошибку обработчика
+// source описывает, где она произошла (например, "command /table" или "callback st")
+// chatID - чат пользователя для ответа; 0, если отвечать некому
+func (r *Reporter) Handle(chatID int64, source string, err error) {
+	if err == nil {
+		return
+	}
+	e := Classify(err)
+	if e.UserFacing {
+		logrus.Infof("User-facing error in %s: %v", source, e)
+	} else {
+		logrus.Errorf("Error in %s: %v", source, e)
+	}
+
+	if chatID != 0 {
+		if _, sendErr := r.bot.Send(tgbotapi.NewMessage(chatID, e.Message)); sendErr != nil {
+			logrus.Errorf("Failed to send error reply to chat %d: %v", chatID, sendErr)
+		}
+	}
+	if !e.UserFacing {
+		r.report(source, e)
+	}
+}
+
+// Отправляет отчёт в админ-чат, если он не дубликат и не превышен лимит
+func (r *Reporter) report(source string, e *Error) {
+	if r.adminChatID == 0 {
+		return
+	}
+	text, ok := r.prepare(source, e)
+	if !ok {
+		return
+	}
+	if _, err := r.bot.Send(tgbotapi.NewMessage(r.adminChatID, text)); err != nil {
+		logrus.Errorf("Failed to send error report to admin chat: %v", err)
+	}
+}
+
+// Решает, нужно ли отправлять отчёт, и собирает его текст
+func (r *Reporter) prepare(source string, e *Error) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	key := fingerprint(source, e)
+	occ, ok := r.seen[key]
+	if ok && now.Sub(occ.reportedAt) < dedupWindow {
+		occ.suppressed++
+		return "", false
+	}
+	if !r.allow(now) {
+		if ok {
+			occ.suppressed++
+		}
+		return "", false
+	}
+
+	suppressed := 0
+	if ok {
+		suppressed = occ.suppressed
+	}
+	r.seen[key] = &occurrence{reportedAt: now}
+	r.cleanup(now)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Ошибка в %s\n%v\n", source, e)
+	if suppressed > 0 {
+		fmt.Fprintf(&b, "Повторялась ещё %d раз с прошлого отчёта\n", suppressed)
+	}
+	if stack := e.Stack(); stack != "" {
+		b.WriteString("\n")
+		b.WriteString(stack)
+	}
+	text := b.String()
+	if len(text) > maxReportLength {
+		text = text[:maxReportLength] + "\n..."
+	}
+	return text, true
+}
+
+// Скользящее окно для ограничения частоты отчётов
+func (r *Reporter) allow(now time.Time) bool {
+	fresh := r.sent[:0]
+	for _, t := range r.sent {
+		if now.Sub(t) < rateWindow {
+			fresh = append(fresh, t)
+		}
+	}
+	r.sent = fresh
+	if len(r.sent) >= rateLimit {
+		return false
+	}
+	r.sent = append(r.sent, now)
+	return true
+}
+
+// Удаляет устаревшие отпечатки без подавленных повторов, чтобы карта не росла бесконечно
+func (r *Reporter) cleanup(now time.Time) {
+	for key, occ := range r.seen {
+		if now.Sub(occ.reportedAt) > dedupWindow && occ.suppressed == 0 {
+			delete(r.seen, key)
+		}
+	}
+}
+
+func fingerprint(source string, e *Error) string {
+	return source + "|" + digits.ReplaceAllString(e.Error(), "N")
+}
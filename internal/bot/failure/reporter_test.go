@@ -0,0 +1,59 @@
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReporterDeduplicates(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	r := NewReporter(nil, 1)
+	r.now = func() time.Time { return now }
+
+	first := Classify(fmt.Errorf("error finding team with ID %d", 57))
+	if _, ok := r.prepare("callback st", first); !ok {
+		t.Fatal("first occurrence must be reported")
+	}
+	second := Classify(fmt.Errorf("error finding team with ID %d", 61))
+	if _, ok := r.prepare("callback st", second); ok {
+		t.Fatal("same error with a different ID must be deduplicated")
+	}
+
+	now = now.Add(dedupWindow + time.Second)
+	text, ok := r.prepare("callback st", second)
+	if !ok {
+		t.Fatal("error must be reported again after the dedup window")
+	}
+	if !strings.Contains(text, "ещё 1 раз") {
+		t.Errorf("report must mention suppressed duplicates, got %q", text)
+	}
+}
+
+func TestReporterRateLimit(t *testing.T) {
+	r := NewReporter(nil, 1)
+	reported := 0
+	for i := 0; i < rateLimit+3; i++ {
+		if _, ok := r.prepare(fmt.Sprintf("source %c", 'a'+i), Classify(errors.New("boom"))); ok {
+			reported++
+		}
+	}
+	if reported != rateLimit {
+		t.Errorf("reported %d errors, want %d", reported, rateLimit)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	if e := Classify(User("нет матчей")); !e.UserFacing || e.Message != "нет матчей" {
+		t.Errorf("unexpected classification of user error: %+v", e)
+	}
+	wrapped := fmt.Errorf("handler: %w", Internal("", errors.New("db down")))
+	if e := Classify(wrapped); e.UserFacing || e.Message != DefaultUserMessage || e.Stack() == "" {
+		t.Errorf("unexpected classification of internal error: %+v", e)
+	}
+	if e := Classify(errors.New("plain")); e.UserFacing {
+		t.Error("plain errors must be internal")
+	}
+}
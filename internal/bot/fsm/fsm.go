@@ -0,0 +1,243 @@
+package fsm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/cache"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// State - шаг многошагового диалога.
+// Пустое состояние StateNone означает, что диалог завершён
+type State string
+
+const StateNone State = ""
+
+// Время жизни шага по умолчанию, если в Step.Timeout не указано иное
+const DefaultStepTimeout = 5 * time.Minute
+
+var (
+	// ErrUnknownDialog возвращается при попытке запустить незарегистрированный диалог
+	ErrUnknownDialog = errors.New("unknown dialog")
+	// ErrInvalidTransition возвращается, если обработчик шага вернул состояние,
+	// которое не объявлено в Step.Next
+	ErrInvalidTransition = errors.New("invalid state transition")
+)
+
+// Интерфейс хранилища сессий; реализуется cache.RedisClient
+type SessionStore interface {
+	SetBytes(ctx context.Context, key string, value []byte, expiration time.Duration) error
+	GetBytes(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Session - состояние диалога пользователя в конкретном чате
+// Data хранит промежуточные ответы пользователя между шагами
+type Session struct {
+	Dialog   string            `json:"dialog"`
+	State    State             `json:"state"`
+	Data     map[string]string `json:"data"`
+	Deadline time.Time         `json:"deadline"`
+}
+
+// StepHandler обрабатывает сообщение пользователя на текущем шаге
+// и возвращает следующее состояние (StateNone - завершить диалог)
+type StepHandler func(ctx context.Context, bot *tgbotapi.BotAPI, msg *tgbotapi.Message, session *Session) (State, error)
+
+// Step - описание одного шага диалога
+// Next - список состояний, в которые разрешено перейти с этого шага
+type Step struct {
+	State   State
+	Handle  StepHandler
+	Next    []State
+	Timeout time.Duration
+}
+
+// Dialog - набор шагов; первый шаг в Steps является начальным
+type Dialog struct {
+	Name  string
+	Steps []Step
+}
+
+// Machine хранит зарегистрированные диалоги и ведёт сессии в Redis
+// Сессия принадлежит паре чат-пользователь: в группе другие участники не могут отвечать в чужой диалог
+type Machine struct {
+	store   SessionStore
+	dialogs map[string]map[State]Step
+	initial map[string]State
+	now     func() time.Time
+}
+
+// Конструктор для создания машины состояний
+func NewMachine(store SessionStore) *Machine {
+	return &Machine{
+		store:   store,
+		dialogs: make(map[string]map[State]Step),
+		initial: make(map[string]State),
+		now:     time.Now,
+	}
+}
+
+// Register регистрирует диалог
+// Проверяет, что все переходы ведут в объявленные шаги этого диалога
+func (m *Machine) Register(dialog Dialog) error {
+	if len(dialog.Steps) == 0 {
+		return fmt.Errorf("dialog %s has no steps", dialog.Name)
+	}
+	steps := make(map[State]Step, len(dialog.Steps))
+	for _, step := range dialog.Steps {
+		if step.State == StateNone {
+			return fmt.Errorf("dialog %s: step with empty state", dialog.Name)
+		}
+		if step.Timeout == 0 {
+			step.Timeout = DefaultStepTimeout
+		}
+		steps[step.State] = step
+	}
+	for _, step := range dialog.Steps {
+		for _, next := range step.Next {
+			if _, ok := steps[next]; !ok && next != StateNone {
+				return fmt.Errorf("dialog %s: step %s declares unknown transition to %s", dialog.Name, step.State, next)
+			}
+		}
+	}
+	m.dialogs[dialog.Name] = steps
+	m.initial[dialog.Name] = dialog.Steps[0].State
+	return nil
+}
+
+// Start начинает диалог пользователя userID в чате chatID с начального шага
+// Предыдущий незавершённый диалог этого пользователя в чате перезаписывается
+func (m *Machine) Start(ctx context.Context, chatID, userID int64, dialog string, data map[string]string) error {
+	initial, ok := m.initial[dialog]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownDialog, dialog)
+	}
+	if data == nil {
+		data = make(map[string]string)
+	}
+	session := &Session{Dialog: dialog, State: initial, Data: data}
+	return m.save(ctx, sessionKey(chatID, userID), session)
+}
+
+// Cancel завершает диалог пользователя в чате
+// Возвращает true, если активный диалог был
+func (m *Machine) Cancel(ctx context.Context, chatID, userID int64) (bool, error) {
+	key := sessionKey(chatID, userID)
+	session, err := m.load(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if session == nil {
+		return false, nil
+	}
+	return true, m.store.Delete(ctx, key)
+}
+
+// Handle передаёт сообщение в текущий шаг диалога его автора
+// Возвращает handled=false, если у автора нет активного диалога в этом чате и сообщение
+// нужно обработать обычным способом
+func (m *Machine) Handle(ctx context.Context, bot *tgbotapi.BotAPI, msg *tgbotapi.Message) (bool, error) {
+	if msg.From == nil {
+		return false, nil
+	}
+	chatID := msg.Chat.ID
+	key := sessionKey(chatID, msg.From.ID)
+	session, err := m.load(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if session == nil {
+		return false, nil
+	}
+
+	if m.now().After(session.Deadline) {
+		if err := m.store.Delete(ctx, key); err != nil {
+			return true, fmt.Errorf("error deleting expired session: %w", err)
+		}
+		_, err := bot.Send(tgbotapi.NewMessage(chatID, "Время ожидания ответа истекло. Начните заново."))
+		return true, err
+	}
+
+	step, ok := m.dialogs[session.Dialog][session.State]
+	if !ok {
+		// Диалог или шаг убрали из кода, пока сессия жила в Redis
+		return true, m.store.Delete(ctx, key)
+	}
+
+	next, err := step.Handle(ctx, bot, msg, session)
+	if err != nil {
+		return true, fmt.Errorf("dialog %s, step %s: %w", session.Dialog, session.State, err)
+	}
+	if next == StateNone {
+		return true, m.store.Delete(ctx, key)
+	}
+	if !allowed(step.Next, next) {
+		return true, fmt.Errorf("%w: %s -> %s in dialog %s", ErrInvalidTransition, session.State, next, session.Dialog)
+	}
+
+	session.State = next
+	return true, m.save(ctx, key, session)
+}
+
+// Проверяет, что переход объявлен в шаге
+func allowed(next []State, state State) bool {
+	for _, s := range next {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Сохраняет сессию и выставляет дедлайн текущего шага
+// TTL в Redis вдвое больше таймаута, чтобы успеть сообщить пользователю об истечении времени
+func (m *Machine) save(ctx context.Context, key string, session *Session) error {
+	timeout := m.dialogs[session.Dialog][session.State].Timeout
+	session.Deadline = m.now().Add(timeout)
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("error encoding session: %w", err)
+	}
+	return m.store.SetBytes(ctx, key, data, 2*timeout)
+}
+
+// Загружает сессию по ключу; возвращает nil, если сессии нет
+func (m *Machine) load(ctx context.Context, key string) (*Session, error) {
+	data, err := m.store.GetBytes(ctx, key)
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading session: %w", err)
+	}
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("error decoding session: %w", err)
+	}
+	return &session, nil
+}
+
+func sessionKey(chatID, userID int64) string {
+	return fmt.Sprintf("fsm:%d:%d", chatID, userID)
+}
+
+// Section, ExportUserData и DeleteUserData реализуют postgres.PersonalDataStore,
+// чтобы незавершённый диалог в личном чате с ботом тоже попадал в /export_me и /delete_me
+// Диалоги в группах ведут только администраторы, и они истекают вместе с шагом
+func (m *Machine) Section() string {
+	return "dialog_session"
+}
+
+func (m *Machine) ExportUserData(ctx context.Context, telegramID int64) (interface{}, error) {
+	return m.load(ctx, sessionKey(telegramID, telegramID))
+}
+
+func (m *Machine) DeleteUserData(ctx context.Context, telegramID int64) error {
+	return m.store.Delete(ctx, sessionKey(telegramID, telegramID))
+}
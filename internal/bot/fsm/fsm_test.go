@@ -0,0 +1,151 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/cache"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// memoryStore - хранилище сессий в памяти для тестов
+type memoryStore map[string][]byte
+
+func (s memoryStore) SetBytes(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	s[key] = value
+	return nil
+}
+
+func (s memoryStore) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	data, ok := s[key]
+	if !ok {
+		return nil, fmt.Errorf("%w for key %s", cache.ErrCacheMiss, key)
+	}
+	return data, nil
+}
+
+func (s memoryStore) Delete(ctx context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+const (
+	stateName    State = "name"
+	stateConfirm State = "confirm"
+)
+
+func newTestMachine(t *testing.T, confirmNext State) (*Machine, memoryStore) {
+	store := memoryStore{}
+	m := NewMachine(store)
+	err := m.Register(Dialog{
+		Name: "follow",
+		Steps: []Step{
+			{
+				State: stateName,
+				Next:  []State{stateConfirm},
+				Handle: func(ctx context.Context, bot *tgbotapi.BotAPI, msg *tgbotapi.Message, s *Session) (State, error) {
+					s.Data["team"] = msg.Text
+					return stateConfirm, nil
+				},
+			},
+			{
+				State: stateConfirm,
+				Next:  []State{StateNone},
+				Handle: func(ctx context.Context, bot *tgbotapi.BotAPI, msg *tgbotapi.Message, s *Session) (State, error) {
+					return confirmNext, nil
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register returned an error: %v", err)
+	}
+	return m, store
+}
+
+func message(text string) *tgbotapi.Message {
+	return messageFrom(7, text)
+}
+
+func messageFrom(userID int64, text string) *tgbotapi.Message {
+	return &tgbotapi.Message{Text: text, Chat: &tgbotapi.Chat{ID: 42}, From: &tgbotapi.User{ID: userID}}
+}
+
+func TestMachineWalksDialog(t *testing.T) {
+	ctx := context.Background()
+	m, store := newTestMachine(t, StateNone)
+
+	if handled, _ := m.Handle(ctx, nil, message("hello")); handled {
+		t.Fatal("message without active dialog must not be handled")
+	}
+	if err := m.Start(ctx, 42, 7, "follow", nil); err != nil {
+		t.Fatalf("Start returned an error: %v", err)
+	}
+	if handled, err := m.Handle(ctx, nil, message("Arsenal")); !handled || err != nil {
+		t.Fatalf("Handle = %v, %v; want true, nil", handled, err)
+	}
+	session, _ := m.load(ctx, sessionKey(42, 7))
+	if session.State != stateConfirm || session.Data["team"] != "Arsenal" {
+		t.Fatalf("unexpected session after first step: %+v", session)
+	}
+	if _, err := m.Handle(ctx, nil, message("yes")); err != nil {
+		t.Fatalf("Handle returned an error: %v", err)
+	}
+	if len(store) != 0 {
+		t.Fatal("session must be removed after the dialog is finished")
+	}
+}
+
+func TestMachineRejectsUndeclaredTransition(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMachine(t, stateName)
+
+	m.Start(ctx, 42, 7, "follow", nil)
+	m.Handle(ctx, nil, message("Arsenal"))
+	_, err := m.Handle(ctx, nil, message("yes"))
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+}
+
+func TestMachineRegisterValidatesTransitions(t *testing.T) {
+	m := NewMachine(memoryStore{})
+	err := m.Register(Dialog{Name: "broken", Steps: []Step{{State: stateName, Next: []State{stateConfirm}}}})
+	if err == nil {
+		t.Fatal("expected an error for transition to undeclared step")
+	}
+}
+
+func TestMachineCancel(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMachine(t, StateNone)
+
+	if cancelled, _ := m.Cancel(ctx, 42, 7); cancelled {
+		t.Fatal("nothing to cancel yet")
+	}
+	m.Start(ctx, 42, 7, "follow", nil)
+	if cancelled, _ := m.Cancel(ctx, 42, 7); !cancelled {
+		t.Fatal("active dialog must be cancelled")
+	}
+}
+
+func TestMachineIgnoresOtherUsersInChat(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMachine(t, StateNone)
+
+	m.Start(ctx, 42, 7, "follow", nil)
+	if handled, _ := m.Handle(ctx, nil, messageFrom(8, "Arsenal")); handled {
+		t.Fatal("another member of the chat must not answer someone else's dialog")
+	}
+	if cancelled, _ := m.Cancel(ctx, 42, 8); cancelled {
+		t.Fatal("another member of the chat must not cancel someone else's dialog")
+	}
+	session, _ := m.load(ctx, sessionKey(42, 7))
+	if session == nil || session.State != stateName {
+		t.Fatalf("initiator's session must be untouched, got %+v", session)
+	}
+}
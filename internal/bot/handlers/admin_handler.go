@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/failure"
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/fsm"
+	resp "github.com/vsespontanno/tgbot_fschedule/internal/bot/response"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Обрабатывает служебные команды; доступ проверяется в HandleMessage
+func handleAdminCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, analyticsService *service.AnalyticsService, rivalryService *service.RivalryService, dialogs *fsm.Machine) error {
+	switch msg.Command() {
+	case "stats":
+		return handleStats(bot, msg, analyticsService)
+	case "rivalries":
+		return handleRivalries(bot, msg, rivalryService)
+	case "rivalry_add":
+		return handleRivalryStart(bot, msg, dialogs, rivalryModeAdd)
+	case "rivalry_edit":
+		return handleRivalryStart(bot, msg, dialogs, rivalryModeEdit)
+	default:
+		return handleUnknownCommand(bot, msg)
+	}
+}
+
+// Обрабатывает команду /stats [YYYY-MM-DD]
+// Показывает администратору агрегированный отчёт об использовании бота за день (по умолчанию за вчера)
+func handleStats(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, analyticsService *service.AnalyticsService) error {
+	day := time.Now().UTC().AddDate(0, 0, -1)
+	if arg := strings.TrimSpace(msg.CommandArguments()); arg != "" {
+		parsed, err := time.Parse("2006-01-02", arg)
+		if err != nil {
+			return failure.User("Укажите дату в формате ГГГГ-ММ-ДД, например /stats 2025-07-01")
+		}
+		day = parsed
+	}
+
+	report, err := analyticsService.HandleGetDailyReport(context.Background(), day)
+	if err != nil {
+		return failure.Internal("Не удалось построить отчёт.", fmt.Errorf("error getting daily report: %w", err))
+	}
+	return resp.SendMessage(bot, msg.Chat.ID, formatUsageReport(report))
+}
+
+// Форматирует отчёт об использовании в текст сообщения
+func formatUsageReport(report *types.UsageReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Статистика за %s\n", report.Day.Format("2006-01-02"))
+	fmt.Fprintf(&b, "Событий: %d, пользователей: %d, ошибок: %d\n", report.Events, report.Users, report.Errors)
+
+	b.WriteString("\nПопулярные команды:\n")
+	for i, c := range report.TopCommands {
+		fmt.Fprintf(&b, "%d. %s - %d\n", i+1, c.Name, c.Count)
+	}
+
+	b.WriteString("\nПопулярные лиги:\n")
+	for i, c := range report.TopLeagues {
+		fmt.Fprintf(&b, "%d. %s - %d\n", i+1, c.Name, c.Count)
+	}
+
+	b.WriteString("\nВремя ответа (p95):\n")
+	for _, l := range report.Latency {
+		fmt.Fprintf(&b, "%s - %.0f мс (%d запросов)\n", l.Name, l.P95Ms, l.Count)
+	}
+	return b.String()
+}
@@ -5,44 +5,74 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"sort"
-	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/callback"
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/failure"
 	"github.com/vsespontanno/tgbot_fschedule/internal/bot/keyboards"
 	resp "github.com/vsespontanno/tgbot_fschedule/internal/bot/response"
 	"github.com/vsespontanno/tgbot_fschedule/internal/cache"
 	"github.com/vsespontanno/tgbot_fschedule/internal/service"
 	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+	"github.com/vsespontanno/tgbot_fschedule/internal/utils"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 // Обработка callback запросов таких как выбор лиги, выбор команды, выбор таблицы через кнопки
-func HandleCallbackQuery(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery, matchService *service.MatchesService, standingsService *service.StandingsService, redisClient *cache.RedisClient) error {
-	switch query.Data {
-	case "show_top_matches":
-		resp.SendCallbackResponse(bot, query.ID)
-		return HandleTopMatches(bot, query, matchService, redisClient, "")
-	case "show_all_matches":
-		resp.SendCallbackResponse(bot, query.ID)
-		return HandleDefaultScheduleCommand(bot, query.Message)
-
+// Данные кнопки разбираются кодеком callback; кнопки старых версий получают просьбу открыть меню заново
+func HandleCallbackQuery(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery, matchService *service.MatchesService, standingsService *service.StandingsService, userService *service.UserService, personalService *service.PersonalizationService, redisClient *cache.RedisClient) error {
+	payload, err := callback.Decode(query.Data)
+	if errors.Is(err, callback.ErrStale) {
+		return resp.SendCallbackAlert(bot, query.ID, "Эта кнопка устарела, пожалуйста, откройте меню заново.")
 	}
-
-	if league, ok := keyboards.KeyboardsStandings[query.Data]; ok {
+	if err != nil {
 		resp.SendCallbackResponse(bot, query.ID)
-		return HandleStandingsCallback(bot, query, standingsService, redisClient, league)
+		return fmt.Errorf("error decoding callback data %q: %w", query.Data, err)
 	}
 
-	if league, ok := keyboards.KeyboardsSchedule[query.Data]; ok {
+	switch payload.Action {
+	case callback.ActionTopMatches:
+		resp.SendCallbackResponse(bot, query.ID)
+		return HandleTopMatches(bot, query, personalService)
+	case callback.ActionExplainTop:
 		resp.SendCallbackResponse(bot, query.ID)
-		return HandleScheduleCallback(bot, query, matchService, redisClient, league, query.Data)
+		return HandleExplainTopMatches(bot, query, personalService)
+	case callback.ActionAllMatches:
+		resp.SendCallbackResponse(bot, query.ID)
+		return HandleDefaultScheduleCommand(bot, query.Message)
+	case callback.ActionDeleteMeConfirm:
+		resp.SendCallbackResponse(bot, query.ID)
+		return HandleDeleteMeConfirm(bot, query, userService)
+	case callback.ActionDeleteMeCancel:
+		resp.SendCallbackResponse(bot, query.ID)
+		return resp.EditMessageText(bot, query.Message.Chat.ID, query.Message.MessageID, "Удаление отменено.")
+	case callback.ActionStandings:
+		if league, ok := keyboards.KeyboardsStandings[payload.Param(0)]; ok {
+			resp.SendCallbackResponse(bot, query.ID)
+			return HandleStandingsCallback(bot, query, standingsService, redisClient, league)
+		}
+	case callback.ActionSchedule:
+		if league, ok := keyboards.KeyboardsSchedule[payload.Param(0)]; ok {
+			resp.SendCallbackResponse(bot, query.ID)
+			return HandleScheduleCallback(bot, query, matchService, redisClient, league, payload.Param(0))
+		}
 	}
 
-	return resp.SendMessage(bot, query.Message.Chat.ID, "Неизвестная команда.")
+	resp.SendCallbackResponse(bot, query.ID)
+	return failure.User("Неизвестная команда.")
+}
+
+// Обработка подтверждения удаления данных пользователя
+// Удаляет данные нажавшего кнопку из всех хранилищ и заменяет сообщение с кнопками на итог
+func HandleDeleteMeConfirm(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery, userService *service.UserService) error {
+	chatID := query.Message.Chat.ID
+	if err := userService.DeleteUserData(context.Background(), query.From.ID); err != nil {
+		return failure.Internal("Не удалось удалить ваши данные, попробуйте позже.", fmt.Errorf("error deleting user data: %w", err))
+	}
+	return resp.EditMessageText(bot, chatID, query.Message.MessageID, "Все ваши данные удалены. Чтобы снова пользоваться ботом, отправьте /start.")
 }
 
 // Обработка колбэков для турнирной таблицы и расписания матчей
@@ -50,19 +80,18 @@ func HandleCallbackQuery(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery, ma
 func HandleStandingsCallback(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery, standingsService *service.StandingsService, redisClient *cache.RedisClient, league types.League) error {
 	standings, err := standingsService.HandleGetStandings(context.Background(), league.CollectionName)
 	if err != nil {
-		return fmt.Errorf("error getting standings: %w", err)
+		return failure.Internal("Не удалось получить турнирную таблицу", fmt.Errorf("error getting standings: %w", err))
 	}
 	imagePath := fmt.Sprintf("%s.png", league.CollectionName)
 	defer os.Remove(imagePath)
 
 	if err := GenerateTableImage(standings, league.Code, imagePath, redisClient); err != nil {
-		return fmt.Errorf("error generating image: %w", err)
+		return failure.Internal("Произошла ошибка при создании изображения с таблицей", fmt.Errorf("error generating image: %w", err))
 	}
 
 	err = resp.SendPhoto(bot, query.Message.Chat.ID, imagePath)
 	if err != nil {
-		resp.SendMessage(bot, query.Message.Chat.ID, "Произошла ошибка при отправке изображения с таблицей")
-		return fmt.Errorf("error sending image for table: %w", err)
+		return failure.Internal("Произошла ошибка при отправке изображения с таблицей", fmt.Errorf("error sending image for table: %w", err))
 	}
 
 	// Отправляем ответ на callback query
@@ -72,14 +101,14 @@ func HandleStandingsCallback(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery
 
 // Обработка колбэков для расписания матчей
 // Здесь мы получаем расписание матчей для выбранной лиги и отправляем его пользователю в виде изображения
-func HandleScheduleCallback(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery, service *service.MatchesService, redisClient *cache.RedisClient, league types.League, button string) error {
+// leagueName - название соревнования из параметра кнопки
+func HandleScheduleCallback(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery, service *service.MatchesService, redisClient *cache.RedisClient, league types.League, leagueName string) error {
 	var (
-		leagueName = strings.TrimPrefix(query.Data, "schedule_")
-		imagePath  = fmt.Sprintf("%s.png", leagueName)
-		cacheKey   = "all_matches_image" + imagePath
-		from       = time.Now()
-		to         = from.AddDate(0, 0, 7)
-		ctx        = context.Background()
+		imagePath = fmt.Sprintf("%s.png", leagueName)
+		cacheKey  = "all_matches_image" + imagePath
+		from      = time.Now()
+		to        = from.AddDate(0, 0, 7)
+		ctx       = context.Background()
 	)
 
 	// Проверяем кэш
@@ -88,8 +117,7 @@ func HandleScheduleCallback(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery,
 		err = resp.SendPhoto(bot, query.Message.Chat.ID, imagePath)
 		resp.SendCallbackResponse(bot, query.ID)
 		if err != nil {
-			resp.SendMessage(bot, query.Message.Chat.ID, "Произошла ошибка при отправке изображения с расписанием")
-			return err
+			return failure.Internal("Произошла ошибка при отправке изображения с расписанием", err)
 		}
 		return nil
 
@@ -100,9 +128,8 @@ func HandleScheduleCallback(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery,
 	matches, err := service.HandleGetMatchesForPeriod(context.Background(), leagueName, from.Format("2006-01-02"), to.Format("2006-01-02"))
 
 	if err != nil {
-		resp.SendMessage(bot, query.Message.Chat.ID, "Произошла ошибка при получении расписания матчей")
 		resp.SendCallbackResponse(bot, query.ID)
-		return err
+		return failure.Internal("Произошла ошибка при получении расписания матчей", err)
 	}
 
 	// Фильтруем матчи только по лиге
@@ -115,82 +142,47 @@ func HandleScheduleCallback(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery,
 	}
 
 	if len(leagueMatches) == 0 {
-		resp.SendMessage(bot, query.Message.Chat.ID, "На ближайшие дни нет матчей в лиге "+leagueName+".")
 		resp.SendCallbackResponse(bot, query.ID)
-		return nil
+		return failure.User("На ближайшие дни нет матчей в лиге " + leagueName + ".")
 	}
 
 	// Генерируем изображение с расписанием
 	if err := GenerateScheduleImage(leagueMatches, imagePath, redisClient); err != nil {
-		resp.SendMessage(bot, query.Message.Chat.ID, "Произошла ошибка при отправке изображения с матчами: \n")
 		resp.SendCallbackResponse(bot, query.ID)
-		return err
+		return failure.Internal("Произошла ошибка при создании изображения с матчами", err)
 	}
 
-	return err
+	if err := resp.SendPhoto(bot, query.Message.Chat.ID, imagePath); err != nil {
+		return failure.Internal("Произошла ошибка при отправке изображения с расписанием", err)
+	}
+	return nil
 }
 
 // Обработка команды для получения расписания топовых матчей
-// Здесь мы получаем топовые матчи за неделю и отправляем их пользователю в виде
-// изображения.
-func HandleTopMatches(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery, service *service.MatchesService, redisClient *cache.RedisClient, button string) error {
-	var (
-		ctx       = context.Background()
-		cacheKey  = "top_matches_image"
-		imagePath = fmt.Sprintf("%s.png", "top_matches")
-		from      = time.Now()
-		to        = from.AddDate(0, 0, 7)
-	)
-
-	// Проверяем кэш
-	if _, err := redisClient.GetBytes(ctx, cacheKey); err == nil {
-		logrus.WithField("cache_key", cacheKey).Info("Cache hit for schedule image")
-		err = resp.SendPhoto(bot, query.Message.Chat.ID, imagePath)
-		if err != nil {
-			resp.SendMessage(bot, query.Message.Chat.ID, "Произошла ошибка при отправке изображения с расписанием")
-			return err
-		}
-		return resp.SendCallbackResponse(bot, query.ID)
-
-	} else if errors.Is(err, redis.Nil) {
-		logrus.WithField("cache_key", cacheKey).Warn("Cache error: ", err)
-	}
-
-	matches, err := service.HandleGetMatchesForPeriod(ctx, "", from.Format("2006-01-02"), to.Format("2006-01-02"))
+// Топ строится для каждого пользователя отдельно с учётом его подписок и скрытых команд и лиг
+// и отправляется в виде изображения
+func HandleTopMatches(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery, personalService *service.PersonalizationService) error {
+	chatID := query.Message.Chat.ID
+	top, err := personalService.HandleGetTopMatches(context.Background(), chatID, topMatchesLimit)
 	if err != nil {
-		return err
+		return failure.Internal("Произошла ошибка при получении топовых матчей", err)
 	}
-
-	if len(matches) == 0 {
-		err = resp.SendMessage(bot, query.Message.Chat.ID, "На ближайшие дни нет топовых матчей.")
-		if err != nil {
-			return err
-		}
-
-		return nil
+	if len(top) == 0 {
+		return failure.User("На ближайшие дни нет топовых матчей.")
 	}
 
-	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].Rating > matches[j].Rating
-	})
-
-	if len(matches) > 13 {
-		matches = matches[:13]
+	matches := make([]types.Match, 0, len(top))
+	for _, item := range top {
+		matches = append(matches, item.Match)
 	}
-
-	if err := GenerateScheduleImage(matches, imagePath, redisClient); err != nil {
-		resp.SendMessage(bot, query.Message.Chat.ID, "Произошла ошибка при создании изображения с топ-матчами")
-		return err
+	buf, err := utils.ScheduleImage(matches)
+	if err != nil {
+		return failure.Internal("Произошла ошибка при создании изображения с топ-матчами", fmt.Errorf("error generating image for top matches: %w", err))
 	}
 
-	err = resp.SendPhoto(bot, query.Message.Chat.ID, imagePath)
+	err = resp.SendPhotoBytesWithKeyboard(bot, chatID, "top_matches.png", buf.Bytes(), keyboards.KeyboardTopMatches)
 	if err != nil {
-		resp.SendMessage(bot, query.Message.Chat.ID, "Произошла ошибка при отправке изображения с таблицей")
-		return fmt.Errorf("error sending image for table: %w", err)
+		return failure.Internal("Произошла ошибка при отправке изображения с топ-матчами", fmt.Errorf("error sending image for top matches: %w", err))
 	}
-
-	resp.SendCallbackResponse(bot, query.ID)
-
-	return err
-
+	return nil
 }
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/failure"
+	resp "github.com/vsespontanno/tgbot_fschedule/internal/bot/response"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// Сколько команд показывает /elo без аргументов
+	eloTopLimit = 20
+	// Сколько последних изменений рейтинга показывается для команды
+	eloHistoryLimit = 5
+)
+
+// Обрабатывает команду /elo [команда]
+// Без аргумента показывает топ команд по рейтингу Эло, с аргументом - карточку команды:
+// рейтинг, последние изменения и зрелищность матчей
+func handleElo(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, eloService *service.EloService, statsService *service.StatsService) error {
+	ctx := context.Background()
+	query := strings.TrimSpace(msg.CommandArguments())
+	if query == "" {
+		top, err := eloService.HandleGetTop(ctx, eloTopLimit)
+		if err != nil {
+			return failure.Internal("Не удалось получить рейтинг Эло.", fmt.Errorf("error getting elo top: %w", err))
+		}
+		if len(top) == 0 {
+			return failure.User("Рейтинг Эло ещё не посчитан.")
+		}
+		return resp.SendMessage(bot, msg.Chat.ID, formatEloTop(top))
+	}
+
+	team, err := eloService.HandleFindTeam(ctx, query)
+	if err != nil {
+		return failure.Internal("Не удалось получить рейтинг Эло.", fmt.Errorf("error finding elo for %q: %w", query, err))
+	}
+	if team == nil {
+		return failure.User(fmt.Sprintf("Команда «%s» не найдена.", query))
+	}
+	entertainment, err := statsService.HandleGetEntertainment(ctx, team.TeamID)
+	if err != nil {
+		return failure.Internal("Не удалось получить статистику команды.", fmt.Errorf("error getting entertainment for team %d: %w", team.TeamID, err))
+	}
+	return resp.SendMessage(bot, msg.Chat.ID, formatTeamElo(team)+formatEntertainment(entertainment))
+}
+
+// Форматирует топ команд по Эло
+func formatEloTop(top []types.TeamElo) string {
+	var b strings.Builder
+	b.WriteString("Рейтинг Эло:\n")
+	for i, t := range top {
+		fmt.Fprintf(&b, "%d. %s - %.0f\n", i+1, t.TeamName, t.Rating)
+	}
+	return b.String()
+}
+
+// Форматирует рейтинг команды и последние изменения
+func formatTeamElo(team *types.TeamElo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %.0f (матчей: %d)\n", team.TeamName, team.Rating, team.Matches)
+	history := team.History
+	if len(history) > eloHistoryLimit {
+		history = history[len(history)-eloHistoryLimit:]
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		p := history[i]
+		date := p.Date
+		if len(date) >= 10 {
+			date = date[:10]
+		}
+		fmt.Fprintf(&b, "%s: %.0f (%+.1f)\n", date, p.Rating, p.Delta)
+	}
+	return b.String()
+}
+
+// Форматирует зрелищность матчей команды
+func formatEntertainment(e types.Entertainment) string {
+	if e.Matches == 0 {
+		return ""
+	}
+	text := fmt.Sprintf("Зрелищность: %.2f (последние %d матчей)\nГолов за матч: %.1f, обе забивают: %.0f%%",
+		e.Index, e.Matches, e.GoalsPerGame, e.BothScoredRate*100)
+	if e.LateSwingMatches > 0 {
+		text += fmt.Sprintf(", исход решился во втором тайме: %.0f%%", e.LateSwingRate*100)
+	}
+	return text
+}
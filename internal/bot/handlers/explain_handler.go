@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/failure"
+	resp "github.com/vsespontanno/tgbot_fschedule/internal/bot/response"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Сколько матчей попадает в топ на изображении
+const topMatchesLimit = 13
+
+// Обработка кнопки "Почему эти матчи?" под изображением топ-матчей
+// Отправляет разбор рейтинга для каждого матча из топа
+// Разбор строится по тому же персональному топу, что и изображение
+func HandleExplainTopMatches(bot *tgbotapi.BotAPI, query *tgbotapi.CallbackQuery, personalService *service.PersonalizationService) error {
+	top, err := personalService.HandleGetTopMatches(context.Background(), query.Message.Chat.ID, topMatchesLimit)
+	if err != nil {
+		return failure.Internal("Не удалось получить разбор топовых матчей", err)
+	}
+	if len(top) == 0 {
+		return failure.User("На ближайшие дни нет топовых матчей.")
+	}
+
+	var b strings.Builder
+	b.WriteString("Почему эти матчи в топе:\n\n")
+	for i, item := range top {
+		fmt.Fprintf(&b, "%d. %s", i+1, explainRating(item.Match))
+		if len(item.Reasons) > 0 {
+			fmt.Fprintf(&b, "\nДля вас: %s", strings.Join(item.Reasons, ", "))
+		}
+		b.WriteString("\n\n")
+	}
+	return resp.SendLongMessage(bot, query.Message.Chat.ID, b.String())
+}
+
+// Форматирует разбор рейтинга одного матча
+func explainRating(match types.Match) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s - %s: %.2f\n", match.HomeTeam.Name, match.AwayTeam.Name, match.Rating)
+
+	r := match.RatingBreakdown
+	if r == nil {
+		b.WriteString("Подробности появятся после следующего пересчёта рейтинга.")
+		return b.String()
+	}
+	if r.HomeElo > 0 {
+		fmt.Fprintf(&b, "Рейтинг Эло: %.0f и %.0f\n", r.HomeElo, r.AwayElo)
+	}
+	fmt.Fprintf(&b, "Сила команд по таблице: %.2f и %.2f → +%.3f\n", r.HomeStrength, r.AwayStrength, r.StrengthPart)
+	fmt.Fprintf(&b, "Вес лиги: %.2f → +%.3f\n", r.LeagueWeight, r.LeaguePart)
+	if r.FormPart > 0 {
+		fmt.Fprintf(&b, "Форма: %.2f и %.2f → +%.3f\n", r.HomeForm, r.AwayForm, r.FormPart)
+		if r.HomeLastFive != "" || r.AwayLastFive != "" {
+			fmt.Fprintf(&b, "Последние матчи: %s и %s\n", formOrDash(r.HomeLastFive), formOrDash(r.AwayLastFive))
+		}
+	}
+	if r.Closeness > 0 {
+		fmt.Fprintf(&b, "Равенство соперников: %.2f\n", r.Closeness)
+	}
+	if p := r.Prediction; p != nil {
+		fmt.Fprintf(&b, "Прогноз: %s, вероятный счёт %s\n", formatOutcomes(*p), p.Score())
+	}
+
+	var bonuses []string
+	if r.DerbyBonus > 0 {
+		bonuses = append(bonuses, fmt.Sprintf("дерби +%.0f%%", r.DerbyBonus*100))
+	}
+	if r.StageBonus > 0 && r.FirstLegScore != "" {
+		bonuses = append(bonuses, fmt.Sprintf("стадия турнира, первый матч %s +%.0f%%", r.FirstLegScore, r.StageBonus*100))
+	} else if r.StageBonus > 0 {
+		bonuses = append(bonuses, fmt.Sprintf("стадия турнира +%.0f%%", r.StageBonus*100))
+	}
+	if r.StakesBonus > 0 {
+		bonuses = append(bonuses, fmt.Sprintf("турнирная значимость +%.0f%%", r.StakesBonus*100))
+	}
+	if r.EntertainmentBonus > 0 {
+		bonuses = append(bonuses, fmt.Sprintf("зрелищные команды +%.0f%%", r.EntertainmentBonus*100))
+	}
+	if r.CrossLeagueBonus > 0 {
+		bonuses = append(bonuses, fmt.Sprintf("команды из разных лиг +%.0f%%", r.CrossLeagueBonus*100))
+	}
+	if len(bonuses) > 0 {
+		fmt.Fprintf(&b, "Бонусы: %s\n", strings.Join(bonuses, ", "))
+	}
+	fmt.Fprintf(&b, "Стратегия: %s v%s", match.RatedBy(), r.StrategyVersion)
+	return b.String()
+}
+
+// Возвращает строку формы или прочерк, если матчей не было
+func formOrDash(lastFive string) string {
+	if lastFive == "" {
+		return "-"
+	}
+	return lastFive
+}
+
+// Форматирует вероятности исходов, например "П1 45% · Х 27% · П2 28%"
+func formatOutcomes(p types.Prediction) string {
+	return fmt.Sprintf("П1 %.0f%% · Х %.0f%% · П2 %.0f%%", p.HomeWin*100, p.Draw*100, p.AwayWin*100)
+}
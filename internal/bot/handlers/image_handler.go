@@ -49,12 +49,7 @@ func GenerateTableImage(data []types.Standing, leagueCode string, filename strin
 
 // функция для генерации изображения расписания матчей
 func GenerateScheduleImage(matches []types.Match, filename string, redisClient *cache.RedisClient) error {
-	var cacheKey string
-	if filename == "" {
-		cacheKey = "top_matches_image"
-	} else {
-		cacheKey = "all_matches_image" + filename
-	}
+	cacheKey := "all_matches_image" + filename
 	const cacheTTL = 6 * time.Hour
 	ctx := context.Background()
 
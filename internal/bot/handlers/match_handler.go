@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/failure"
+	resp "github.com/vsespontanno/tgbot_fschedule/internal/bot/response"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Обрабатывает команду /match <команда>
+// Показывает ближайший матч команды: рейтинг, вероятности исходов и самый вероятный счёт
+func handleMatchDetails(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, statsService *service.StatsService) error {
+	ctx := context.Background()
+	query := strings.TrimSpace(msg.CommandArguments())
+	if query == "" {
+		return failure.User("Укажите команду, например: /match Arsenal")
+	}
+	match, err := statsService.HandleGetNextMatch(ctx, query)
+	if err != nil {
+		return failure.Internal("Не удалось найти матч.", fmt.Errorf("error finding next match for %q: %w", query, err))
+	}
+	if match == nil {
+		return failure.User(fmt.Sprintf("Не нашли ближайших матчей команды «%s».", query))
+	}
+	prediction, err := statsService.HandleGetPrediction(ctx, *match)
+	if err != nil {
+		return failure.Internal("Не удалось посчитать прогноз.", fmt.Errorf("error predicting match %d: %w", match.ID, err))
+	}
+	return resp.SendMessage(bot, msg.Chat.ID, formatMatchDetails(*match, prediction))
+}
+
+// Форматирует карточку матча
+func formatMatchDetails(match types.Match, prediction *types.Prediction) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s - %s\n", match.HomeTeam.Name, match.AwayTeam.Name)
+	fmt.Fprintf(&b, "%s, %s %s UTC\n", match.Competition.Name, match.UTCDate[0:10], match.UTCDate[11:16])
+	if match.Rating > 0 {
+		fmt.Fprintf(&b, "Рейтинг: %.2f\n", match.Rating)
+	}
+	if prediction == nil {
+		b.WriteString("Для прогноза пока мало сыгранных матчей.")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Победа %s: %.0f%%\n", match.HomeTeam.Name, prediction.HomeWin*100)
+	fmt.Fprintf(&b, "Ничья: %.0f%%\n", prediction.Draw*100)
+	fmt.Fprintf(&b, "Победа %s: %.0f%%\n", match.AwayTeam.Name, prediction.AwayWin*100)
+	fmt.Fprintf(&b, "Вероятный счёт: %s (%.0f%%)\n", prediction.Score(), prediction.ScoreProbability*100)
+	fmt.Fprintf(&b, "Ожидаемые голы: %.2f - %.2f", prediction.HomeExpected, prediction.AwayExpected)
+	return b.String()
+}
@@ -2,10 +2,14 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"log"
 
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/failure"
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/fsm"
 	"github.com/vsespontanno/tgbot_fschedule/internal/bot/keyboards"
 	resp "github.com/vsespontanno/tgbot_fschedule/internal/bot/response"
+	"github.com/vsespontanno/tgbot_fschedule/internal/config"
 	"github.com/vsespontanno/tgbot_fschedule/internal/service"
 	"github.com/vsespontanno/tgbot_fschedule/internal/types"
 
@@ -13,33 +17,80 @@ import (
 )
 
 // Обрабатывает все входящие сообщения
-func HandleMessage(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, userService *service.UserService) error {
+// Если в чате идёт многошаговый диалог, обычный текст уходит в него,
+// а команды по-прежнему обрабатываются как обычно
+// Служебные команды доступны только администраторам из конфига
+func HandleMessage(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, cfg *config.Config, userService *service.UserService, analyticsService *service.AnalyticsService, eloService *service.EloService, rivalryService *service.RivalryService, personalService *service.PersonalizationService, statsService *service.StatsService, upsetService *service.UpsetService, dialogs *fsm.Machine) error {
 	if msg.Text == "" {
 		return nil
 	}
-	switch msg.Text {
-	case "/start":
+	if !msg.IsCommand() {
+		handled, err := dialogs.Handle(context.Background(), bot, msg)
+		if handled || err != nil {
+			return err
+		}
+	}
+	switch msg.Command() {
+	case "start":
 		return handleStart(bot, msg, userService)
-	case "/help":
+	case "help":
 		return handleHelp(bot, msg)
-	case "/schedule":
+	case "schedule":
 		return handleScheduleCommand(bot, msg)
-	case "/table":
+	case "table":
 		return handleTableCommand(bot, msg)
+	case "elo":
+		return handleElo(bot, msg, eloService, statsService)
+	case "match":
+		return handleMatchDetails(bot, msg, statsService)
+	case "shocks":
+		return handleShocks(bot, msg, upsetService)
+	case "follow":
+		return handleSavePreference(bot, msg, personalService, types.PreferenceFollow)
+	case "hide":
+		return handleSavePreference(bot, msg, personalService, types.PreferenceHide)
+	case "unfollow":
+		return handleDeletePreference(bot, msg, personalService)
+	case "prefs":
+		return handlePreferences(bot, msg, personalService)
+	case "export_me":
+		return handleExportMe(bot, msg, userService)
+	case "delete_me":
+		return handleDeleteMe(bot, msg)
+	case "cancel":
+		return handleCancel(bot, msg, dialogs)
+	case "stats", "rivalries", "rivalry_add", "rivalry_edit":
+		if msg.From == nil || !cfg.IsAdmin(msg.From.ID) {
+			return handleUnknownCommand(bot, msg)
+		}
+		return handleAdminCommand(bot, msg, analyticsService, rivalryService, dialogs)
 	default:
 		return handleUnknownCommand(bot, msg)
 	}
 }
 
+// Возвращает Telegram ID автора сообщения
+// Личные данные привязаны к пользователю, а не к чату: в группе у чата другой ID
+func senderID(msg *tgbotapi.Message) (int64, error) {
+	if msg.From == nil {
+		return 0, failure.User("Эта команда доступна только пользователям.")
+	}
+	return msg.From.ID, nil
+}
+
 // Обрабатывает команду /start
 func handleStart(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, userService *service.UserService) error {
 	ctx := context.Background()
+	telegramID, err := senderID(msg)
+	if err != nil {
+		return err
+	}
 	user := &types.User{
-		TelegramID: msg.Chat.ID,
-		Username:   msg.Chat.UserName,
+		TelegramID: telegramID,
+		Username:   msg.From.UserName,
 	}
 
-	err := userService.SaveUser(ctx, user)
+	err = userService.SaveUser(ctx, user)
 	if err != nil {
 		log.Printf("error saving user: %v", err)
 		return err
@@ -47,6 +98,8 @@ func handleStart(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, userService *servi
 	response := "Привет! Я бот для отслеживания футбольных матчей. Доступные команды:\n" +
 		"/schedule - показать расписание матчей\n" +
 		"/table - показать турнирную таблицу\n" +
+		"/export_me - выгрузить все данные, которые бот хранит о вас\n" +
+		"/delete_me - удалить все ваши данные\n" +
 		"/help - показать справку"
 
 	return resp.SendMessage(bot, msg.Chat.ID, response)
@@ -57,6 +110,16 @@ func handleHelp(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
 	response := "Привет! Я бот для отслеживания футбольных матчей. Доступные команды:\n" +
 		"/schedule - показать расписание всех матчей\n" +
 		"/table - показать турнирную таблицу\n" +
+		"/elo [команда] - рейтинг Эло команд и зрелищность их матчей\n" +
+		"/match <команда> - прогноз ближайшего матча команды\n" +
+		"/shocks - главные сенсации недели\n" +
+		"/follow <команда или лига> - поднимать матчи в топе и присылать сенсации лиги\n" +
+		"/hide <команда или лига> - не показывать матчи в топе\n" +
+		"/unfollow <команда или лига> - снять подписку или скрытие\n" +
+		"/prefs - ваши подписки\n" +
+		"/export_me - выгрузить все данные, которые бот хранит о вас\n" +
+		"/delete_me - удалить все ваши данные\n" +
+		"/cancel - прервать текущее действие\n" +
 		"/help - показать справку"
 	return resp.SendMessage(bot, msg.Chat.ID, response)
 }
@@ -73,6 +136,46 @@ func handleScheduleCommand(bot *tgbotapi.BotAPI, message *tgbotapi.Message) erro
 	return resp.SendMessageWithKeyboard(bot, message.Chat.ID, text, keyboards.Keyboard_Schedule)
 }
 
+// Обрабатывает команду /export_me
+// Собирает все данные пользователя из хранилищ и отправляет их JSON-файлом
+func handleExportMe(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, userService *service.UserService) error {
+	telegramID, err := senderID(msg)
+	if err != nil {
+		return err
+	}
+	data, err := userService.ExportUserData(context.Background(), telegramID)
+	if err != nil {
+		return failure.Internal("Не удалось выгрузить ваши данные, попробуйте позже.", fmt.Errorf("error exporting user data: %w", err))
+	}
+	return resp.SendDocument(bot, msg.Chat.ID, "export_me.json", data)
+}
+
+// Обрабатывает команду /delete_me
+// Само удаление происходит только после подтверждения кнопкой
+func handleDeleteMe(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
+	if _, err := senderID(msg); err != nil {
+		return err
+	}
+	text := "Удалить все данные, которые бот хранит о вас? Это действие нельзя отменить."
+	return resp.SendMessageWithKeyboard(bot, msg.Chat.ID, text, keyboards.KeyboardDeleteMe)
+}
+
+// Обрабатывает команду /cancel
+// Прерывает текущий многошаговый диалог, если он есть
+func handleCancel(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, dialogs *fsm.Machine) error {
+	if msg.From == nil {
+		return failure.User("Нечего отменять.")
+	}
+	cancelled, err := dialogs.Cancel(context.Background(), msg.Chat.ID, msg.From.ID)
+	if err != nil {
+		return fmt.Errorf("error cancelling dialog: %w", err)
+	}
+	if !cancelled {
+		return failure.User("Нечего отменять.")
+	}
+	return resp.SendMessage(bot, msg.Chat.ID, "Действие отменено.")
+}
+
 // Обрабатывает неизвестные команды
 // Отправляет сообщение о том, что команда не распознана
 func handleUnknownCommand(bot *tgbotapi.BotAPI, msg *tgbotapi.Message) error {
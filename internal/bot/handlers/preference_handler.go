@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/failure"
+	resp "github.com/vsespontanno/tgbot_fschedule/internal/bot/response"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Обрабатывает команды /follow и /hide <команда или лига>
+// /follow поднимает матчи команды или лиги в персональном топе, /hide убирает их из топа
+func handleSavePreference(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, personalService *service.PersonalizationService, mode string) error {
+	query := strings.TrimSpace(msg.CommandArguments())
+	if query == "" {
+		return failure.User(fmt.Sprintf("Укажите команду или лигу, например: /%s Arsenal или /%s EPL", msg.Command(), msg.Command()))
+	}
+	pref, err := personalService.HandleSavePreference(context.Background(), msg.Chat.ID, query, mode)
+	if err != nil {
+		return failure.Internal("Не удалось сохранить настройку, попробуйте позже.", fmt.Errorf("error saving preference %q: %w", query, err))
+	}
+	if pref == nil {
+		return failure.User(fmt.Sprintf("Команда или лига «%s» не найдена.", query))
+	}
+	text := fmt.Sprintf("Матчи %s будут выше в вашем топе.", pref.Label)
+	if mode == types.PreferenceHide {
+		text = fmt.Sprintf("Матчи %s больше не будут попадать в ваш топ.", pref.Label)
+	}
+	return resp.SendMessage(bot, msg.Chat.ID, text)
+}
+
+// Обрабатывает команду /unfollow <команда или лига>
+// Снимает и подписку, и скрытие
+func handleDeletePreference(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, personalService *service.PersonalizationService) error {
+	query := strings.TrimSpace(msg.CommandArguments())
+	if query == "" {
+		return failure.User("Укажите команду или лигу, например: /unfollow Arsenal")
+	}
+	pref, err := personalService.HandleDeletePreference(context.Background(), msg.Chat.ID, query)
+	if err != nil {
+		return failure.Internal("Не удалось изменить настройку, попробуйте позже.", fmt.Errorf("error deleting preference %q: %w", query, err))
+	}
+	if pref == nil {
+		return failure.User(fmt.Sprintf("«%s» нет в ваших подписках и скрытых.", query))
+	}
+	return resp.SendMessage(bot, msg.Chat.ID, fmt.Sprintf("Настройка для %s снята.", pref.Label))
+}
+
+// Обрабатывает команду /prefs
+// Показывает подписки и скрытые команды и лиги
+func handlePreferences(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, personalService *service.PersonalizationService) error {
+	prefs, err := personalService.HandleGetPreferences(context.Background(), msg.Chat.ID)
+	if err != nil {
+		return failure.Internal("Не удалось получить ваши настройки, попробуйте позже.", fmt.Errorf("error getting preferences: %w", err))
+	}
+	if len(prefs) == 0 {
+		return failure.User("У вас нет подписок. Добавьте команду или лигу: /follow Arsenal, /follow EPL")
+	}
+	return resp.SendMessage(bot, msg.Chat.ID, formatPreferences(prefs))
+}
+
+// Форматирует список предпочтений пользователя
+func formatPreferences(prefs []types.Preference) string {
+	var followed, hidden []string
+	for _, p := range prefs {
+		if p.Mode == types.PreferenceHide {
+			hidden = append(hidden, p.Label)
+		} else {
+			followed = append(followed, p.Label)
+		}
+	}
+	var b strings.Builder
+	if len(followed) > 0 {
+		fmt.Fprintf(&b, "Вы следите за: %s\n", strings.Join(followed, ", "))
+	}
+	if len(hidden) > 0 {
+		fmt.Fprintf(&b, "Скрыто из топа: %s\n", strings.Join(hidden, ", "))
+	}
+	b.WriteString("Снять настройку: /unfollow <команда или лига>")
+	return b.String()
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/failure"
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/fsm"
+	resp "github.com/vsespontanno/tgbot_fschedule/internal/bot/response"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Диалог добавления и изменения дерби
+const dialogRivalry = "rivalry"
+
+const (
+	stateRivalryFirstTeam  fsm.State = "first_team"
+	stateRivalrySecondTeam fsm.State = "second_team"
+	stateRivalryIntensity  fsm.State = "intensity"
+	stateRivalryNotes      fsm.State = "notes"
+)
+
+// Режимы диалога: добавление новой пары или изменение существующей
+const (
+	rivalryModeAdd  = "add"
+	rivalryModeEdit = "edit"
+)
+
+// Ответ, которым администратор оставляет заметку без изменений
+const keepNotes = "-"
+
+// RegisterRivalryDialog регистрирует диалог /rivalry_add и /rivalry_edit
+// isAdmin проверяется на каждом шаге: права могли отозвать, пока диалог ждал ответа
+func RegisterRivalryDialog(dialogs *fsm.Machine, rivalryService *service.RivalryService, isAdmin func(int64) bool) error {
+	return dialogs.Register(fsm.Dialog{
+		Name: dialogRivalry,
+		Steps: []fsm.Step{
+			{
+				State:  stateRivalryFirstTeam,
+				Handle: adminOnly(isAdmin, rivalryFirstTeamStep(rivalryService)),
+				Next:   []fsm.State{stateRivalrySecondTeam},
+			},
+			{
+				State:  stateRivalrySecondTeam,
+				Handle: adminOnly(isAdmin, rivalrySecondTeamStep(rivalryService)),
+				Next:   []fsm.State{stateRivalryIntensity, fsm.StateNone},
+			},
+			{
+				State:  stateRivalryIntensity,
+				Handle: adminOnly(isAdmin, rivalryIntensityStep),
+				Next:   []fsm.State{stateRivalryNotes},
+			},
+			{
+				State:  stateRivalryNotes,
+				Handle: adminOnly(isAdmin, rivalryNotesStep(rivalryService)),
+				Next:   []fsm.State{fsm.StateNone},
+			},
+		},
+	})
+}
+
+// Обрабатывает команду /rivalries
+// Показывает администратору весь реестр дерби
+func handleRivalries(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, rivalryService *service.RivalryService) error {
+	rivalries, err := rivalryService.HandleGetRivalries(context.Background())
+	if err != nil {
+		return failure.Internal("Не удалось получить список дерби.", fmt.Errorf("error getting rivalries: %w", err))
+	}
+	if len(rivalries) == 0 {
+		return failure.User("Список дерби пуст. Добавьте дерби через /rivalry_add.")
+	}
+	var b strings.Builder
+	b.WriteString("Дерби:\n")
+	for _, r := range rivalries {
+		fmt.Fprintf(&b, "%s - %s: %.2f", r.TeamAName, r.TeamBName, r.Intensity)
+		if r.Notes != "" {
+			fmt.Fprintf(&b, " (%s)", r.Notes)
+		}
+		b.WriteString("\n")
+	}
+	return resp.SendLongMessage(bot, msg.Chat.ID, b.String())
+}
+
+// Обрабатывает команды /rivalry_add и /rivalry_edit: запускает диалог в нужном режиме
+func handleRivalryStart(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, dialogs *fsm.Machine, mode string) error {
+	if err := dialogs.Start(context.Background(), msg.Chat.ID, msg.From.ID, dialogRivalry, map[string]string{"mode": mode}); err != nil {
+		return fmt.Errorf("error starting rivalry dialog: %w", err)
+	}
+	return resp.SendMessage(bot, msg.Chat.ID, "Введите первую команду (название или ID). /cancel - отменить.")
+}
+
+// Завершает диалог, если у автора ответа больше нет прав администратора
+func adminOnly(isAdmin func(int64) bool, next fsm.StepHandler) fsm.StepHandler {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, msg *tgbotapi.Message, session *fsm.Session) (fsm.State, error) {
+		if msg.From == nil || !isAdmin(msg.From.ID) {
+			return fsm.StateNone, resp.SendMessage(bot, msg.Chat.ID, "Изменять список дерби могут только администраторы.")
+		}
+		return next(ctx, bot, msg, session)
+	}
+}
+
+func rivalryFirstTeamStep(rivalryService *service.RivalryService) fsm.StepHandler {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, msg *tgbotapi.Message, session *fsm.Session) (fsm.State, error) {
+		team, err := findRivalryTeam(ctx, rivalryService, msg.Text)
+		if err != nil {
+			return fsm.StateNone, err
+		}
+		session.Data["first_id"] = strconv.Itoa(team.ID)
+		session.Data["first_name"] = team.Name
+		return stateRivalrySecondTeam, resp.SendMessage(bot, msg.Chat.ID, fmt.Sprintf("Первая команда: %s. Введите вторую команду.", team.Name))
+	}
+}
+
+func rivalrySecondTeamStep(rivalryService *service.RivalryService) fsm.StepHandler {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, msg *tgbotapi.Message, session *fsm.Session) (fsm.State, error) {
+		team, err := findRivalryTeam(ctx, rivalryService, msg.Text)
+		if err != nil {
+			return fsm.StateNone, err
+		}
+		firstID, _ := strconv.Atoi(session.Data["first_id"])
+		if team.ID == firstID {
+			return fsm.StateNone, failure.User("Нужна другая команда. Введите вторую команду.")
+		}
+
+		existing, err := rivalryService.HandleGetRivalry(ctx, firstID, team.ID)
+		if err != nil {
+			return fsm.StateNone, failure.Internal("", fmt.Errorf("error getting rivalry: %w", err))
+		}
+		pair := fmt.Sprintf("%s - %s", session.Data["first_name"], team.Name)
+		switch {
+		case session.Data["mode"] == rivalryModeAdd && existing != nil:
+			text := fmt.Sprintf("Дерби %s уже есть (накал %.2f). Измените его через /rivalry_edit.", pair, existing.Intensity)
+			return fsm.StateNone, resp.SendMessage(bot, msg.Chat.ID, text)
+		case session.Data["mode"] == rivalryModeEdit && existing == nil:
+			text := fmt.Sprintf("Дерби %s нет в списке. Добавьте его через /rivalry_add.", pair)
+			return fsm.StateNone, resp.SendMessage(bot, msg.Chat.ID, text)
+		}
+
+		session.Data["second_id"] = strconv.Itoa(team.ID)
+		session.Data["second_name"] = team.Name
+		text := fmt.Sprintf("Дерби %s. Введите накал от 0 до 1, например 0.25.", pair)
+		if existing != nil {
+			session.Data["notes"] = existing.Notes
+			text = fmt.Sprintf("Дерби %s, текущий накал %.2f. Введите новый накал от 0 до 1.", pair, existing.Intensity)
+		}
+		return stateRivalryIntensity, resp.SendMessage(bot, msg.Chat.ID, text)
+	}
+}
+
+func rivalryIntensityStep(ctx context.Context, bot *tgbotapi.BotAPI, msg *tgbotapi.Message, session *fsm.Session) (fsm.State, error) {
+	intensity, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(msg.Text), ",", "."), 64)
+	if err != nil || intensity < 0 || intensity > 1 {
+		return fsm.StateNone, failure.User("Накал должен быть числом от 0 до 1, например 0.25.")
+	}
+	session.Data["intensity"] = strconv.FormatFloat(intensity, 'f', -1, 64)
+
+	text := fmt.Sprintf("Введите заметку (например, название дерби) или «%s», чтобы оставить без заметки.", keepNotes)
+	if notes := session.Data["notes"]; notes != "" {
+		text = fmt.Sprintf("Текущая заметка: %s. Введите новую или «%s», чтобы оставить как есть.", notes, keepNotes)
+	}
+	return stateRivalryNotes, resp.SendMessage(bot, msg.Chat.ID, text)
+}
+
+func rivalryNotesStep(rivalryService *service.RivalryService) fsm.StepHandler {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, msg *tgbotapi.Message, session *fsm.Session) (fsm.State, error) {
+		if text := strings.TrimSpace(msg.Text); text != keepNotes {
+			session.Data["notes"] = text
+		}
+		firstID, _ := strconv.Atoi(session.Data["first_id"])
+		secondID, _ := strconv.Atoi(session.Data["second_id"])
+		intensity, _ := strconv.ParseFloat(session.Data["intensity"], 64)
+
+		rivalry := types.Rivalry{
+			TeamA:     firstID,
+			TeamB:     secondID,
+			TeamAName: session.Data["first_name"],
+			TeamBName: session.Data["second_name"],
+			Intensity: intensity,
+			Notes:     session.Data["notes"],
+		}
+		if err := rivalryService.HandleSaveRivalry(ctx, rivalry); err != nil {
+			return fsm.StateNone, failure.Internal("Не удалось сохранить дерби.", fmt.Errorf("error saving rivalry: %w", err))
+		}
+		text := fmt.Sprintf("Сохранено: %s - %s, накал %.2f. Бонус применится при следующем пересчёте рейтинга.", rivalry.TeamAName, rivalry.TeamBName, rivalry.Intensity)
+		return fsm.StateNone, resp.SendMessage(bot, msg.Chat.ID, text)
+	}
+}
+
+// Ищет команду по вводу администратора; если не нашлась, просит повторить ввод
+func findRivalryTeam(ctx context.Context, rivalryService *service.RivalryService, query string) (*types.Team, error) {
+	team, err := rivalryService.HandleFindTeam(ctx, query)
+	if err != nil {
+		return nil, failure.Internal("", fmt.Errorf("error finding team: %w", err))
+	}
+	if team == nil {
+		return nil, failure.User(fmt.Sprintf("Команда «%s» не найдена. Введите название или ID ещё раз.", strings.TrimSpace(query)))
+	}
+	return team, nil
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/failure"
+	resp "github.com/vsespontanno/tgbot_fschedule/internal/bot/response"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+	"github.com/vsespontanno/tgbot_fschedule/internal/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// Сколько сенсаций показывается на картинке недели
+const shocksLimit = 10
+
+// Обрабатывает команду /shocks
+// Присылает картинку с самыми неожиданными результатами последней недели
+func handleShocks(bot *tgbotapi.BotAPI, msg *tgbotapi.Message, upsetService *service.UpsetService) error {
+	upsets, err := upsetService.HandleGetWeeklyShocks(context.Background(), shocksLimit)
+	if err != nil {
+		return failure.Internal("Не удалось найти сенсации недели.", fmt.Errorf("error getting weekly shocks: %w", err))
+	}
+	if len(upsets) == 0 {
+		return failure.User("За неделю сенсаций не случилось.")
+	}
+	buf, err := utils.ShocksImage(upsets)
+	if err != nil {
+		return failure.Internal("Произошла ошибка при создании изображения с сенсациями", fmt.Errorf("error generating shocks image: %w", err))
+	}
+	if err := resp.SendPhotoBytesWithKeyboard(bot, msg.Chat.ID, "shocks.png", buf.Bytes(), nil); err != nil {
+		return failure.Internal("Произошла ошибка при отправке изображения с сенсациями", fmt.Errorf("error sending shocks image: %w", err))
+	}
+	return nil
+}
+
+// SendUpsetAlerts рассылает уведомления о сенсациях подписчикам лиг
+// Ошибка отправки одному пользователю (например, он заблокировал бота) не мешает остальным
+func SendUpsetAlerts(bot *tgbotapi.BotAPI, alerts []types.UpsetAlert) int {
+	sent := 0
+	for _, alert := range alerts {
+		text := FormatUpsetAlert(alert.Upset)
+		for _, chatID := range alert.Recipients {
+			if err := resp.SendMessage(bot, chatID, text); err != nil {
+				logrus.WithError(err).WithField("chat_id", chatID).Warn("Failed to send upset alert")
+				continue
+			}
+			sent++
+		}
+	}
+	return sent
+}
+
+// FormatUpsetAlert форматирует уведомление о сенсации
+func FormatUpsetAlert(upset types.Upset) string {
+	match := upset.Match
+	text := fmt.Sprintf("Сенсация в %s: %s %d:%d %s\n", match.Competition.Name,
+		match.HomeTeam.Name, match.Score.FullTime.Home, match.Score.FullTime.Away, match.AwayTeam.Name)
+	if upset.Basis == types.UpsetBasisTable {
+		return text + fmt.Sprintf("%s (%d-е место) против %s (%d-е место).",
+			match.HomeTeam.Name, upset.HomePosition, match.AwayTeam.Name, upset.AwayPosition)
+	}
+	return text + fmt.Sprintf("До матча у такого исхода было %.0f%% шансов.", upset.ResultProbability*100)
+}
@@ -1,6 +1,7 @@
 package keyboards
 
 import (
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/callback"
 	"github.com/vsespontanno/tgbot_fschedule/internal/types"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -8,67 +9,83 @@ import (
 
 var (
 
-	// Мапа для выбора лиги для турнирной таблицы на основе нажатой кнопки
+	// Мапа для выбора лиги для турнирной таблицы по параметру кнопки
 	KeyboardsStandings = map[string]types.League{
-		"standings_EPL":        types.Leagues["PremierLeague"],
-		"standings_LaLiga":     types.Leagues["LaLiga"],
-		"standings_Bundesliga": types.Leagues["Bundesliga"],
-		"standings_SerieA":     types.Leagues["SerieA"],
-		"standings_Ligue1":     types.Leagues["Ligue1"],
-		"standings_CL":         types.Leagues["ChampionsLeague"],
+		"EPL":        types.Leagues["PremierLeague"],
+		"LaLiga":     types.Leagues["LaLiga"],
+		"Bundesliga": types.Leagues["Bundesliga"],
+		"SerieA":     types.Leagues["SerieA"],
+		"Ligue1":     types.Leagues["Ligue1"],
+		"CL":         types.Leagues["ChampionsLeague"],
 	}
 
-	// Мапа для выбора лиги для расписания матчей на основе нажатой кнопки
+	// Мапа для выбора лиги для расписания матчей по параметру кнопки
+	// Параметр совпадает с названием соревнования в матчах после tools.MatchFilter
 	KeyboardsSchedule = map[string]types.League{
-		"schedule_LaLiga":     types.Leagues["LaLiga"],
-		"schedule_EPL":        types.Leagues["PremierLeague"],
-		"schedule_Primeira":   types.Leagues["Primeira"],
-		"schedule_Eredivisie": types.Leagues["Eredivisie"],
-		"schedule_Bundesliga": types.Leagues["Bundesliga"],
-		"schedule_SerieA":     types.Leagues["SerieA"],
-		"schedule_UCL":        types.Leagues["ChampionsLeague"],
-		"schedule_UEL":        types.Leagues["EuropaLeague"],
+		"LaLiga":     types.Leagues["LaLiga"],
+		"EPL":        types.Leagues["PremierLeague"],
+		"Primeira":   types.Leagues["Primeira"],
+		"Eredivisie": types.Leagues["Eredivisie"],
+		"Bundesliga": types.Leagues["Bundesliga"],
+		"SerieA":     types.Leagues["SerieA"],
+		"UCL":        types.Leagues["ChampionsLeague"],
+		"UEL":        types.Leagues["EuropaLeague"],
 	}
 
 	// Инлайн-клавиатура для выбора лиг для турнирной таблицы
 	KeyboardStandings = tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("EPL", "standings_EPL"),
-			tgbotapi.NewInlineKeyboardButtonData("La Liga", "standings_LaLiga"),
+			callback.Button("EPL", callback.ActionStandings, "EPL"),
+			callback.Button("La Liga", callback.ActionStandings, "LaLiga"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Bundesliga", "standings_Bundesliga"),
-			tgbotapi.NewInlineKeyboardButtonData("Serie A", "standings_SerieA"),
+			callback.Button("Bundesliga", callback.ActionStandings, "Bundesliga"),
+			callback.Button("Serie A", callback.ActionStandings, "SerieA"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Ligue 1", "standings_Ligue1"),
-			tgbotapi.NewInlineKeyboardButtonData("Champions League", "standings_CL"),
+			callback.Button("Ligue 1", callback.ActionStandings, "Ligue1"),
+			callback.Button("Champions League", callback.ActionStandings, "CL"),
 		),
 	)
 
 	// Инлайн-клавиатура для выбора лиг
 	KeyboardDefaultSchedule = tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("La Liga", "schedule_LaLiga"),
-			tgbotapi.NewInlineKeyboardButtonData("EPL", "schedule_EPL"),
+			callback.Button("La Liga", callback.ActionSchedule, "LaLiga"),
+			callback.Button("EPL", callback.ActionSchedule, "EPL"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Primeira", "schedule_Primeira"),
-			tgbotapi.NewInlineKeyboardButtonData("Eredivisie", "schedule_Eredivisie"),
+			callback.Button("Primeira", callback.ActionSchedule, "Primeira"),
+			callback.Button("Eredivisie", callback.ActionSchedule, "Eredivisie"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Bundesliga", "schedule_Bundesliga"),
-			tgbotapi.NewInlineKeyboardButtonData("Serie A", "schedule_SerieA"),
+			callback.Button("Bundesliga", callback.ActionSchedule, "Bundesliga"),
+			callback.Button("Serie A", callback.ActionSchedule, "SerieA"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("UCL", "schedule_UCL"),
-			tgbotapi.NewInlineKeyboardButtonData("UEL", "schedule_UEL"),
+			callback.Button("UCL", callback.ActionSchedule, "UCL"),
+			callback.Button("UEL", callback.ActionSchedule, "UEL"),
 		),
 	)
 	Keyboard_Schedule = tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("Все матчи", "show_all_matches"),
-			tgbotapi.NewInlineKeyboardButtonData("Топ матчи", "show_top_matches"),
+			callback.Button("Все матчи", callback.ActionAllMatches),
+			callback.Button("Топ матчи", callback.ActionTopMatches),
+		),
+	)
+
+	// Инлайн-клавиатура для подтверждения удаления данных пользователя
+	KeyboardDeleteMe = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			callback.Button("Да, удалить", callback.ActionDeleteMeConfirm),
+			callback.Button("Отмена", callback.ActionDeleteMeCancel),
+		),
+	)
+
+	// Инлайн-клавиатура под изображением топ-матчей
+	KeyboardTopMatches = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			callback.Button("Почему эти матчи?", callback.ActionExplainTop),
 		),
 	)
 )
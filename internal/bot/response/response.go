@@ -1,6 +1,8 @@
 package response
 
 import (
+	"strings"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -32,3 +34,62 @@ func SendPhoto(bot *tgbotapi.BotAPI, chatID int64, path string) error {
 	_, err := bot.Send(photo)
 	return err
 }
+
+// Функция для отправки файла из памяти
+func SendDocument(bot *tgbotapi.BotAPI, chatID int64, name string, data []byte) error {
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	_, err := bot.Send(doc)
+	return err
+}
+
+// Функция для редактирования текста сообщения с удалением клавиатуры
+func EditMessageText(bot *tgbotapi.BotAPI, chatID int64, messageID int, text string) error {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	_, err := bot.Send(edit)
+	return err
+}
+
+// Функция для ответа на callback запрос всплывающим уведомлением
+func SendCallbackAlert(bot *tgbotapi.BotAPI, queryID string, text string) error {
+	callback := tgbotapi.NewCallbackWithAlert(queryID, text)
+	_, err := bot.Request(callback)
+	return err
+}
+
+// Функция для отправки фото с клавиатурой под ним
+func SendPhotoWithKeyboard(bot *tgbotapi.BotAPI, chatID int64, path string, keyboard interface{}) error {
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FilePath(path))
+	photo.ReplyMarkup = keyboard
+	_, err := bot.Send(photo)
+	return err
+}
+
+// Telegram не принимает сообщения длиннее 4096 символов, оставляем запас
+const maxMessageLength = 4000
+
+// Функция для отправки длинного текста несколькими сообщениями
+// Текст режется по границам абзацев (пустым строкам)
+func SendLongMessage(bot *tgbotapi.BotAPI, chatID int64, text string) error {
+	var chunk strings.Builder
+	for _, part := range strings.SplitAfter(text, "\n\n") {
+		if chunk.Len() > 0 && len([]rune(chunk.String()))+len([]rune(part)) > maxMessageLength {
+			if err := SendMessage(bot, chatID, chunk.String()); err != nil {
+				return err
+			}
+			chunk.Reset()
+		}
+		chunk.WriteString(part)
+	}
+	if chunk.Len() == 0 {
+		return nil
+	}
+	return SendMessage(bot, chatID, chunk.String())
+}
+
+// Функция для отправки фото из памяти с клавиатурой под ним
+func SendPhotoBytesWithKeyboard(bot *tgbotapi.BotAPI, chatID int64, name string, data []byte, keyboard interface{}) error {
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: name, Bytes: data})
+	photo.ReplyMarkup = keyboard
+	_, err := bot.Send(photo)
+	return err
+}
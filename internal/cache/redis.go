@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,6 +10,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ErrCacheMiss возвращается, когда ключа нет в Redis.
+var ErrCacheMiss = errors.New("cache miss")
+
 // RedisClient обертка для клиента Redis.
 type RedisClient struct {
 	client *redis.Client
@@ -46,7 +50,7 @@ func (c *RedisClient) SetBytes(ctx context.Context, key string, value []byte, ex
 func (c *RedisClient) GetBytes(ctx context.Context, key string) ([]byte, error) {
 	data, err := c.client.Get(ctx, key).Bytes()
 	if err == redis.Nil {
-		return nil, fmt.Errorf("cache miss for key %s", key)
+		return nil, fmt.Errorf("%w for key %s", ErrCacheMiss, key)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get from cache: %w", err)
@@ -54,6 +58,12 @@ func (c *RedisClient) GetBytes(ctx context.Context, key string) ([]byte, error)
 	return data, nil
 }
 
+// Метод удаляет ключ из Redis.
+// Отсутствие ключа не считается ошибкой.
+func (c *RedisClient) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, key).Err()
+}
+
 // Метод удаляет все ключи, соответствующие шаблону.
 // Использует SCAN для безопасного удаления ключей в больших базах данных.
 // Возвращает ошибку, если не удалось сканировать ключи или удалить их.
@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"time"
 
 	"github.com/vsespontanno/tgbot_fschedule/internal/tools"
 	"github.com/vsespontanno/tgbot_fschedule/internal/types"
@@ -15,95 +15,134 @@ type MatchApiClient interface {
 	FetchMatches(ctx context.Context, from, to string) ([]types.Match, error)
 }
 
+// season - год начала сезона; 0 - текущий сезон по мнению провайдера
 type StandingsApiClient interface {
-	FetchStandings(ctx context.Context, leagueCode string) ([]types.Standing, error)
+	FetchStandings(ctx context.Context, leagueCode string, season int) ([]types.Standing, error)
 }
 
 type TeamsApiClient interface {
 	FetchTeams(ctx context.Context, leagueCode string) ([]types.Team, error)
 }
 
+type CompetitionsApiClient interface {
+	FetchSeason(ctx context.Context, leagueCode string) (types.Season, error)
+}
+
 // Структура для клиента API футбольных данных
-// Реализует интерфейсы MatchApiClient, StandingsApiClient и TeamsApiClient
+// Реализует интерфейсы MatchApiClient, StandingsApiClient, TeamsApiClient и CompetitionsApiClient
 // Использует http.Client для выполнения запросов к API
 // Принимает API ключ для аутентификации запросов
+// Запросы проходят через общий RateLimiter и повторяются после 429 и ошибок сервера
 // Возвращает данные в виде структур, определенных в types пакете
 type FootballAPIClient struct {
 	httpClient *http.Client
 	apiKey     string
+	baseURL    string
+	limiter    *RateLimiter
+	maxRetries int
+	backoff    time.Duration
+	// Каталог, куда сохраняются успешные ответы API; пусто - ответы не сохраняются
+	recordDir string
 }
 
+// Адрес API football-data
+const footballDataBaseURL = "https://api.football-data.org/v4"
+
 // Конструктор для создания нового клиента API
-func NewFootballAPIClient(httpClient *http.Client, apiKey string) *FootballAPIClient {
+// Клиенты одного процесса должны делить limiter, чтобы вместе не превышать лимит API;
+// если limiter не передан, создаётся свой на лимит бесплатного тарифа
+func NewFootballAPIClient(httpClient *http.Client, apiKey string, limiter *RateLimiter) *FootballAPIClient {
+	if limiter == nil {
+		limiter = NewRateLimiter(FreeTierRequestsPerMinute)
+	}
 	return &FootballAPIClient{
 		httpClient: httpClient,
 		apiKey:     apiKey,
+		baseURL:    footballDataBaseURL,
+		limiter:    limiter,
+		maxRetries: maxRetries,
+		backoff:    retryBackoff,
 	}
 }
 
+// Имя провайдера для реестра
+func (m *FootballAPIClient) Name() string {
+	return FootballDataProvider
+}
+
+// RecordTo включает запись ответов API в каталог dir для FileAPIClient
+func (m *FootballAPIClient) RecordTo(dir string) {
+	m.recordDir = dir
+}
+
 // Реализация метода FetchMatches для получения матчей из API
 // Принимает контекст, даты начала и окончания матчей
 // Возвращает список матчей или ошибку в случае неудачи
 func (m *FootballAPIClient) FetchMatches(ctx context.Context, from, to string) ([]types.Match, error) {
-	url := fmt.Sprintf("https://api.football-data.org/v4/matches?dateFrom=%s&dateTo=%s", from, to)
-	req, err := http.NewRequest("GET", url, nil)
+	url := fmt.Sprintf("%s/matches?dateFrom=%s&dateTo=%s", m.baseURL, from, to)
+	body, err := m.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
+	return decodeMatches(body)
+}
 
-	req.Header.Add("X-Auth-Token", m.apiKey)
-
-	resp, err := m.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %v", err)
+// Реализация метода FetchStandings для получения турнирных таблиц из API
+// Принимает контекст, код лиги и год начала сезона (0 - текущий сезон)
+// Возвращает список стоячих команд или ошибку в случае неудачи
+func (m *FootballAPIClient) FetchStandings(ctx context.Context, leagueCode string, season int) ([]types.Standing, error) {
+	url := fmt.Sprintf("%s/competitions/%s/standings", m.baseURL, leagueCode)
+	if season > 0 {
+		url += fmt.Sprintf("?season=%d", season)
 	}
-
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	body, err := m.get(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %v", err)
+		return nil, err
 	}
-	var MatchesResponse types.MatchesResponse
-	err = json.Unmarshal(body, &MatchesResponse)
+	return decodeStandings(body, leagueCode)
+}
+
+// Реализация метода FetchTeams для получения команд из API
+// Принимает контекст и код лиги
+// Возвращает список команд или ошибку в случае неудачи
+func (m *FootballAPIClient) FetchTeams(ctx context.Context, leagueCode string) ([]types.Team, error) {
+	url := fmt.Sprintf("%s/competitions/%s/teams", m.baseURL, leagueCode)
+	body, err := m.get(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling JSON: %v", err)
+		return nil, err
 	}
-
-	filteredMatches := tools.MatchFilter(MatchesResponse)
-
-	return filteredMatches, nil
+	return decodeTeams(body)
 }
 
-// Реализация метода FetchStandings для получения турнирных таблиц из API
+// Реализация метода FetchSeason для получения текущего сезона соревнования из API
 // Принимает контекст и код лиги
-// Возвращает список стоячих команд или ошибку в случае неудачи
-func (m *FootballAPIClient) FetchStandings(ctx context.Context, leagueCode string) ([]types.Standing, error) {
-	url := fmt.Sprintf("https://api.football-data.org/v4/competitions/%s/standings?season=2025", leagueCode)
-	req, err := http.NewRequest("GET", url, nil)
+// Возвращает даты и текущий тур сезона или ошибку в случае неудачи
+func (m *FootballAPIClient) FetchSeason(ctx context.Context, leagueCode string) (types.Season, error) {
+	url := fmt.Sprintf("%s/competitions/%s", m.baseURL, leagueCode)
+	body, err := m.get(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %s", err)
+		return types.Season{}, err
 	}
+	return decodeSeason(body, leagueCode)
+}
 
-	req.Header.Add("X-Auth-Token", m.apiKey)
-	resp, err := m.httpClient.Do(req)
+// Разбирает ответ /matches и оставляет матчи нужных лиг
+func decodeMatches(body []byte) ([]types.Match, error) {
+	var MatchesResponse types.MatchesResponse
+	err := json.Unmarshal(body, &MatchesResponse)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %s", err)
+		return nil, fmt.Errorf("error unmarshaling JSON: %v", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API request failed with status code: %d, response: %s", resp.StatusCode, string(body))
-	}
+	filteredMatches := tools.MatchFilter(MatchesResponse)
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %s", err)
-	}
+	return filteredMatches, nil
+}
 
+// Разбирает ответ /competitions/{code}/standings
+func decodeStandings(body []byte, leagueCode string) ([]types.Standing, error) {
 	var standingsResponse types.StandingsResponse
-	err = json.Unmarshal(body, &standingsResponse)
+	err := json.Unmarshal(body, &standingsResponse)
 	if err != nil {
 		return nil, fmt.Errorf("error unmarshaling JSON: %s, body: %s", err, string(body))
 	}
@@ -115,30 +154,10 @@ func (m *FootballAPIClient) FetchStandings(ctx context.Context, leagueCode strin
 	return nil, fmt.Errorf("no standings found for league code: %s", leagueCode)
 }
 
-// Реализация метода FetchTeams для получения команд из API
-// Принимает контекст и код лиги
-// Возвращает список команд или ошибку в случае неудачи
-func (m *FootballAPIClient) FetchTeams(ctx context.Context, leagueCode string) ([]types.Team, error) {
-	url := fmt.Sprintf("https://api.football-data.org/v4/competitions/%s/teams", leagueCode)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("X-Auth-Token", m.apiKey)
-	resp, err := m.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
+// Разбирает ответ /competitions/{code}/teams
+func decodeTeams(body []byte) ([]types.Team, error) {
 	var teamsResponse types.TeamsResponse
-	err = json.Unmarshal(body, &teamsResponse)
+	err := json.Unmarshal(body, &teamsResponse)
 	if err != nil {
 		return nil, err
 	}
@@ -146,3 +165,22 @@ func (m *FootballAPIClient) FetchTeams(ctx context.Context, leagueCode string) (
 
 	return teamsResponse.Teams, nil
 }
+
+// Разбирает ответ /competitions/{code}
+func decodeSeason(body []byte, leagueCode string) (types.Season, error) {
+	var competition types.CompetitionResponse
+	if err := json.Unmarshal(body, &competition); err != nil {
+		return types.Season{}, fmt.Errorf("error unmarshaling JSON: %v", err)
+	}
+	if competition.CurrentSeason.StartDate == "" {
+		return types.Season{}, fmt.Errorf("no current season found for league code: %s", leagueCode)
+	}
+	current := competition.CurrentSeason
+	return types.Season{
+		Code:            leagueCode,
+		ID:              current.ID,
+		StartDate:       current.StartDate,
+		EndDate:         current.EndDate,
+		CurrentMatchday: current.CurrentMatchday,
+	}, nil
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// FileAPIClient отвечает записанными ответами football-data из каталога без обращения к сети
+// Реализует интерфейсы MatchApiClient, StandingsApiClient, TeamsApiClient и CompetitionsApiClient;
+// ответы записывает FootballAPIClient в режиме RecordTo
+type FileAPIClient struct {
+	dir string
+}
+
+// Конструктор для создания клиента записанных ответов
+func NewFileAPIClient(dir string) *FileAPIClient {
+	return &FileAPIClient{dir: dir}
+}
+
+// Записанные ответы - это ответы football-data, поэтому и имя провайдера то же
+func (f *FileAPIClient) Name() string {
+	return FootballDataProvider
+}
+
+// Реализация метода FetchMatches: матчи из всех записанных ответов /matches
+// с датой от from до to включительно; повторяющиеся матчи берутся из более позднего файла
+func (f *FileAPIClient) FetchMatches(ctx context.Context, from, to string) ([]types.Match, error) {
+	files, err := f.recordings("matches")
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]types.Match)
+	for _, file := range files {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading recording %s: %w", file, err)
+		}
+		matches, err := decodeMatches(body)
+		if err != nil {
+			return nil, fmt.Errorf("recording %s: %w", file, err)
+		}
+		for _, m := range matches {
+			byID[m.ID] = m
+		}
+	}
+
+	var result []types.Match
+	for _, m := range byID {
+		if len(m.UTCDate) < 10 {
+			continue
+		}
+		if day := m.UTCDate[:10]; day >= from && day <= to {
+			result = append(result, m)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].UTCDate != result[j].UTCDate {
+			return result[i].UTCDate < result[j].UTCDate
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// Реализация метода FetchStandings: записанная таблица лиги за сезон
+// или последняя записанная, если сезон не указан
+func (f *FileAPIClient) FetchStandings(ctx context.Context, leagueCode string, season int) ([]types.Standing, error) {
+	prefix := "competitions_" + leagueCode + "_standings"
+	if season > 0 {
+		prefix += fmt.Sprintf("_season-%d", season)
+	}
+	body, err := f.latest(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return decodeStandings(body, leagueCode)
+}
+
+// Реализация метода FetchTeams: последний записанный список команд лиги
+func (f *FileAPIClient) FetchTeams(ctx context.Context, leagueCode string) ([]types.Team, error) {
+	body, err := f.latest("competitions_" + leagueCode + "_teams")
+	if err != nil {
+		return nil, err
+	}
+	return decodeTeams(body)
+}
+
+// Реализация метода FetchSeason: записанный ответ /competitions/{code}
+func (f *FileAPIClient) FetchSeason(ctx context.Context, leagueCode string) (types.Season, error) {
+	body, err := os.ReadFile(filepath.Join(f.dir, "competitions_"+leagueCode+".json"))
+	if err != nil {
+		return types.Season{}, fmt.Errorf("no recorded competition %s in %s: %w", leagueCode, f.dir, err)
+	}
+	return decodeSeason(body, leagueCode)
+}
+
+// Файлы записанных ответов с данным префиксом, отсортированные по имени
+func (f *FileAPIClient) recordings(prefix string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(f.dir, prefix+"*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing recordings: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Содержимое последнего по имени записанного ответа с данным префиксом
+func (f *FileAPIClient) latest(prefix string) ([]byte, error) {
+	files, err := f.recordings(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recorded response %s in %s", prefix, f.dir)
+	}
+	body, err := os.ReadFile(files[len(files)-1])
+	if err != nil {
+		return nil, fmt.Errorf("error reading recording: %w", err)
+	}
+	return body, nil
+}
+
+// Сохраняет успешный ответ API для FileAPIClient; ошибка записи не мешает работе клиента
+func (m *FootballAPIClient) record(rawURL string, body []byte) {
+	name, err := recordingName(rawURL)
+	if err == nil {
+		err = os.MkdirAll(m.recordDir, 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(filepath.Join(m.recordDir, name), body, 0o644)
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("url", rawURL).Warn("Failed to record API response")
+	}
+}
+
+// Имя файла записи по адресу запроса: путь после /v4 и параметры запроса через "_",
+// например competitions_PL_standings_season-2025.json
+func recordingName(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimPrefix(strings.Trim(u.Path, "/"), "v4/")
+	parts := strings.Split(path, "/")
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, key+"-"+query.Get(key))
+	}
+	return strings.Join(parts, "_") + ".json", nil
+}
+
+// NewFootballData выбирает источник данных football-data
+// Если задан replayDir, данные читаются из записанных ответов без сети и ключ не нужен;
+// иначе используется API, а при заданном recordDir его ответы сохраняются для последующего воспроизведения
+// Для API нужна корзина запросов: лимит ключа делится между процессами, и своя корзина по умолчанию его бы превысила
+func NewFootballData(apiKey, replayDir, recordDir string, limiter *RateLimiter) (Provider, error) {
+	if replayDir != "" {
+		return NewFileAPIClient(replayDir), nil
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("FOOTBALL_DATA_API_KEY is not set and FOOTBALL_DATA_REPLAY_DIR is empty")
+	}
+	if limiter == nil {
+		return nil, fmt.Errorf("rate limiter for football-data is not set")
+	}
+	api := NewFootballAPIClient(NewHTTPClient(), apiKey, limiter)
+	if recordDir != "" {
+		api.RecordTo(recordDir)
+	}
+	return api, nil
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	responses := map[string]string{
+		"/competitions/PL/standings": `{"standings":[{"table":[{"position":1,"team":{"id":57,"name":"Arsenal FC"},"points":70}]}]}`,
+		"/competitions/PL":           `{"code":"PL","currentSeason":{"id":2403,"startDate":"2025-08-15","endDate":"2026-05-24","currentMatchday":8}}`,
+		"/matches": `{"matches":[
+			{"id":1,"competition":{"name":"Premier League","code":"PL"},"utcDate":"2025-03-01T15:00:00Z","status":"FINISHED"},
+			{"id":2,"competition":{"name":"Serie A","code":"SA"},"utcDate":"2025-03-05T19:45:00Z","status":"TIMED"}
+		]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	api := testClient(server)
+	api.RecordTo(dir)
+	ctx := context.Background()
+	if _, err := api.FetchStandings(ctx, "PL", 2025); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.FetchSeason(ctx, "PL"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.FetchMatches(ctx, "2025-03-01", "2025-03-07"); err != nil {
+		t.Fatal(err)
+	}
+
+	replay := NewFileAPIClient(dir)
+	standings, err := replay.FetchStandings(ctx, "PL", 2025)
+	if err != nil || len(standings) != 1 || standings[0].Team.ID != 57 {
+		t.Errorf("replayed standings = %+v, %v", standings, err)
+	}
+	if _, err := replay.FetchStandings(ctx, "PL", 2024); err == nil {
+		t.Error("expected an error for a season that was never recorded")
+	}
+	season, err := replay.FetchSeason(ctx, "PL")
+	if err != nil || season.Year() != 2025 || season.CurrentMatchday != 8 || season.EndDate != "2026-05-24" {
+		t.Errorf("replayed season = %+v, %v", season, err)
+	}
+	matches, err := replay.FetchMatches(ctx, "2025-03-04", "2025-03-10")
+	if err != nil || len(matches) != 1 || matches[0].ID != 2 || matches[0].Competition.Name != "SerieA" {
+		t.Errorf("replayed matches = %+v, %v; want only the filtered match 2", matches, err)
+	}
+	if _, err := replay.FetchTeams(ctx, "PL"); err == nil {
+		t.Error("expected an error for a response that was never recorded")
+	}
+}
+
+func TestRecordingName(t *testing.T) {
+	cases := map[string]string{
+		"https://api.football-data.org/v4/competitions/PL/standings?season=2025":         "competitions_PL_standings_season-2025.json",
+		"https://api.football-data.org/v4/matches?dateTo=2025-03-10&dateFrom=2025-03-01": "matches_dateFrom-2025-03-01_dateTo-2025-03-10.json",
+		"https://api.football-data.org/v4/competitions/CL/teams":                         "competitions_CL_teams.json",
+	}
+	for url, want := range cases {
+		if got, err := recordingName(url); err != nil || got != want {
+			t.Errorf("recordingName(%s) = %q, %v; want %q", url, got, err, want)
+		}
+	}
+}
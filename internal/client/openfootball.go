@@ -0,0 +1,357 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Имя провайдера локальных файлов openfootball
+const OpenFootballProviderName = "openfootball"
+
+// Короткие названия соревнований, как их сохраняет tools.MatchFilter
+var competitionNames = map[string]string{
+	"PL":  "EPL",
+	"PD":  "LaLiga",
+	"BL1": "Bundesliga",
+	"SA":  "SerieA",
+	"FL1": "Ligue1",
+	"CL":  "UCL",
+	"EL":  "UEL",
+	"FAC": "FACup",
+	"DFB": "DFBPokal",
+	"CDR": "CopaDelRey",
+}
+
+// Настройки провайдера openfootball
+// Competitions связывает имя файла без .json (например "en.1") с кодом соревнования,
+// Teams - названия команд из файлов с ID football-data,
+// TimeZones - часовой пояс, в котором в файле указано время матчей (по умолчанию UTC)
+type OpenFootballConfig struct {
+	Dir string `json:"dir"`
+	IDMapping
+	TimeZones map[string]string `json:"timeZones"`
+}
+
+// OpenFootballProvider читает матчи из JSON-файлов в формате openfootball/football.json
+// Таблицы и списки команд строятся по матчам из файла
+// ID матчей у него отрицательные, чтобы не пересекаться с ID football-data
+type OpenFootballProvider struct {
+	dir       string
+	mapping   IDMapping
+	locations map[string]*time.Location
+}
+
+// Конструктор для создания провайдера openfootball
+func NewOpenFootballProvider(cfg OpenFootballConfig) (*OpenFootballProvider, error) {
+	p := &OpenFootballProvider{dir: cfg.Dir, mapping: cfg.IDMapping, locations: make(map[string]*time.Location)}
+	for file, name := range cfg.TimeZones {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("openfootball time zone for %s: %w", file, err)
+		}
+		p.locations[file] = loc
+	}
+	return p, nil
+}
+
+func (p *OpenFootballProvider) Name() string {
+	return OpenFootballProviderName
+}
+
+// Файл openfootball: список матчей сезона
+type openFootballFile struct {
+	Name    string `json:"name"`
+	Matches []struct {
+		Round string `json:"round"`
+		Date  string `json:"date"`
+		Time  string `json:"time"`
+		Team1 string `json:"team1"`
+		Team2 string `json:"team2"`
+		Score struct {
+			FT []int `json:"ft"`
+			HT []int `json:"ht"`
+		} `json:"score"`
+	} `json:"matches"`
+}
+
+// Реализация метода FetchMatches: матчи всех файлов с датой от from до to включительно
+// Матчи команд без соответствия в Teams пропускаются
+func (p *OpenFootballProvider) FetchMatches(ctx context.Context, from, to string) ([]types.Match, error) {
+	var result []types.Match
+	for file, code := range p.mapping.Competitions {
+		matches, err := p.load(file, code)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if day := m.UTCDate[:10]; day >= from && day <= to {
+				result = append(result, m)
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].UTCDate < result[j].UTCDate
+	})
+	return result, nil
+}
+
+// Реализация метода FetchStandings: таблица считается по сыгранным матчам файла
+// В файле один сезон, поэтому таблица другого сезона - ошибка, и реестр спросит следующего провайдера
+func (p *OpenFootballProvider) FetchStandings(ctx context.Context, leagueCode string, season int) ([]types.Standing, error) {
+	file, ok := p.fileOf(leagueCode)
+	if !ok {
+		return nil, fmt.Errorf("openfootball has no file for league code: %s", leagueCode)
+	}
+	if season > 0 {
+		current, err := p.FetchSeason(ctx, leagueCode)
+		if err != nil {
+			return nil, err
+		}
+		if current.Year() != season {
+			return nil, fmt.Errorf("openfootball file %s holds season %d, not %d", file, current.Year(), season)
+		}
+	}
+	matches, err := p.load(file, leagueCode)
+	if err != nil {
+		return nil, err
+	}
+	return BuildStandings(matches), nil
+}
+
+// Реализация метода FetchSeason: даты первого и последнего матча файла
+// Текущий тур - тур первого несыгранного матча, а если сыграны все - последний
+func (p *OpenFootballProvider) FetchSeason(ctx context.Context, leagueCode string) (types.Season, error) {
+	file, ok := p.fileOf(leagueCode)
+	if !ok {
+		return types.Season{}, fmt.Errorf("openfootball has no file for league code: %s", leagueCode)
+	}
+	parsed, err := p.read(file)
+	if err != nil {
+		return types.Season{}, err
+	}
+	season := types.Season{Code: leagueCode}
+	for _, raw := range parsed.Matches {
+		if season.StartDate == "" || raw.Date < season.StartDate {
+			season.StartDate = raw.Date
+		}
+		if raw.Date > season.EndDate {
+			season.EndDate = raw.Date
+		}
+		matchday := roundNumber(raw.Round)
+		if len(raw.Score.FT) != 2 {
+			if season.CurrentMatchday == 0 || matchday < season.CurrentMatchday {
+				season.CurrentMatchday = matchday
+			}
+		}
+	}
+	if season.StartDate == "" {
+		return types.Season{}, fmt.Errorf("openfootball file %s has no matches", file)
+	}
+	if season.CurrentMatchday == 0 {
+		for _, raw := range parsed.Matches {
+			if matchday := roundNumber(raw.Round); matchday > season.CurrentMatchday {
+				season.CurrentMatchday = matchday
+			}
+		}
+	}
+	return season, nil
+}
+
+// Номер тура из названия вроде "Matchday 8"; 0, если номера нет
+func roundNumber(round string) int {
+	n, digits := 0, false
+	for _, r := range round {
+		switch {
+		case r >= '0' && r <= '9':
+			n, digits = n*10+int(r-'0'), true
+		case digits:
+			return n
+		}
+	}
+	return n
+}
+
+// Реализация метода FetchTeams: команды, встречающиеся в матчах файла
+func (p *OpenFootballProvider) FetchTeams(ctx context.Context, leagueCode string) ([]types.Team, error) {
+	file, ok := p.fileOf(leagueCode)
+	if !ok {
+		return nil, fmt.Errorf("openfootball has no file for league code: %s", leagueCode)
+	}
+	matches, err := p.load(file, leagueCode)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int]bool)
+	var teams []types.Team
+	add := func(id int, name string) {
+		if !seen[id] {
+			seen[id] = true
+			teams = append(teams, types.Team{ID: id, Name: name, ShortName: name})
+		}
+	}
+	for _, m := range matches {
+		add(m.HomeTeam.ID, m.HomeTeam.Name)
+		add(m.AwayTeam.ID, m.AwayTeam.Name)
+	}
+	return teams, nil
+}
+
+// Имя файла соревнования
+func (p *OpenFootballProvider) fileOf(code string) (string, bool) {
+	for file, c := range p.mapping.Competitions {
+		if c == code {
+			return file, true
+		}
+	}
+	return "", false
+}
+
+// Читает файл соревнования
+func (p *OpenFootballProvider) read(file string) (*openFootballFile, error) {
+	data, err := os.ReadFile(filepath.Join(p.dir, file+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading openfootball file %s: %w", file, err)
+	}
+	var parsed openFootballFile
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("error parsing openfootball file %s: %w", file, err)
+	}
+	return &parsed, nil
+}
+
+// Читает файл соревнования и переводит матчи в канонические ID
+func (p *OpenFootballProvider) load(file, code string) ([]types.Match, error) {
+	parsed, err := p.read(file)
+	if err != nil {
+		return nil, err
+	}
+	loc := p.locations[file]
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	matches := make([]types.Match, 0, len(parsed.Matches))
+	for _, raw := range parsed.Matches {
+		homeID, okHome := p.mapping.Team(raw.Team1)
+		awayID, okAway := p.mapping.Team(raw.Team2)
+		if !okHome || !okAway {
+			logrus.WithFields(logrus.Fields{"file": file, "home": raw.Team1, "away": raw.Team2}).Warn("Skipping openfootball match with unmapped team")
+			continue
+		}
+		clock := raw.Time
+		if clock == "" {
+			clock = "00:00"
+		}
+		kickoff, err := time.ParseInLocation("2006-01-02 15:04", raw.Date+" "+clock, loc)
+		if err != nil {
+			return nil, fmt.Errorf("openfootball file %s: bad date %q %q: %w", file, raw.Date, raw.Time, err)
+		}
+
+		var m types.Match
+		m.ID = openFootballMatchID(code, raw.Date, homeID, awayID)
+		m.Competition.Name, m.Competition.Code = competitionNames[code], code
+		m.Stage = "REGULAR_SEASON"
+		m.HomeTeam.ID, m.HomeTeam.Name = homeID, raw.Team1
+		m.AwayTeam.ID, m.AwayTeam.Name = awayID, raw.Team2
+		m.UTCDate = kickoff.UTC().Format(time.RFC3339)
+		m.Status = "TIMED"
+		if len(raw.Score.FT) == 2 {
+			m.Status = "FINISHED"
+			m.Score.FullTime.Home, m.Score.FullTime.Away = raw.Score.FT[0], raw.Score.FT[1]
+			m.Score.Winner = winner(raw.Score.FT[0], raw.Score.FT[1])
+			if len(raw.Score.HT) == 2 {
+				m.Score.HalfTime = &types.HalfTimeScore{Home: raw.Score.HT[0], Away: raw.Score.HT[1]}
+			}
+		}
+		matches = append(matches, m)
+	}
+	return matches, nil
+}
+
+// Постоянный отрицательный ID матча по соревнованию, дате и командам
+func openFootballMatchID(code, date string, homeID, awayID int) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s|%s|%d|%d", code, date, homeID, awayID)
+	return -int(h.Sum32()&0x7fffffff) - 1
+}
+
+// Победитель матча в обозначениях football-data
+func winner(home, away int) string {
+	switch {
+	case home > away:
+		return "HOME_TEAM"
+	case home < away:
+		return "AWAY_TEAM"
+	default:
+		return "DRAW"
+	}
+}
+
+// BuildStandings строит таблицу по сыгранным матчам: 3 очка за победу,
+// при равенстве очков выше команда с лучшей разницей мячей, затем с большим числом забитых
+func BuildStandings(matches []types.Match) []types.Standing {
+	rows := make(map[int]*types.Standing)
+	row := func(id int, name string) *types.Standing {
+		s, ok := rows[id]
+		if !ok {
+			s = &types.Standing{Team: types.Team{ID: id, Name: name, ShortName: name}}
+			rows[id] = s
+		}
+		return s
+	}
+	for _, m := range matches {
+		home, away := row(m.HomeTeam.ID, m.HomeTeam.Name), row(m.AwayTeam.ID, m.AwayTeam.Name)
+		if m.Status != "FINISHED" {
+			continue
+		}
+		hg, ag := m.Score.FullTime.Home, m.Score.FullTime.Away
+		home.PlayedGames++
+		away.PlayedGames++
+		home.GoalsFor, home.GoalsAgainst = home.GoalsFor+hg, home.GoalsAgainst+ag
+		away.GoalsFor, away.GoalsAgainst = away.GoalsFor+ag, away.GoalsAgainst+hg
+		switch {
+		case hg > ag:
+			home.Won++
+			away.Lost++
+		case hg < ag:
+			away.Won++
+			home.Lost++
+		default:
+			home.Draw++
+			away.Draw++
+		}
+	}
+
+	standings := make([]types.Standing, 0, len(rows))
+	for _, s := range rows {
+		s.Points = 3*s.Won + s.Draw
+		s.GoalDifference = s.GoalsFor - s.GoalsAgainst
+		standings = append(standings, *s)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.GoalDifference != b.GoalDifference {
+			return a.GoalDifference > b.GoalDifference
+		}
+		if a.GoalsFor != b.GoalsFor {
+			return a.GoalsFor > b.GoalsFor
+		}
+		return a.Team.Name < b.Team.Name
+	})
+	for i := range standings {
+		standings[i].Position = i + 1
+	}
+	return standings
+}
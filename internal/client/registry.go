@@ -0,0 +1,259 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Имя провайдера football-data.org; его ID команд и коды соревнований считаются каноническими
+const FootballDataProvider = "football-data"
+
+// Provider - источник футбольных данных
+// Провайдер возвращает данные уже в канонических ID команд и кодах соревнований
+type Provider interface {
+	Name() string
+	MatchApiClient
+	StandingsApiClient
+	TeamsApiClient
+	CompetitionsApiClient
+}
+
+// IDMapping связывает ID провайдера с каноническими: названия или ID команд провайдера
+// с ID команд football-data и коды соревнований провайдера с кодами football-data
+type IDMapping struct {
+	Teams        map[string]int    `json:"teams"`
+	Competitions map[string]string `json:"competitions"`
+}
+
+// Team возвращает канонический ID команды провайдера
+func (m IDMapping) Team(providerID string) (int, bool) {
+	id, ok := m.Teams[providerID]
+	return id, ok
+}
+
+// Competition возвращает канонический код соревнования провайдера
+func (m IDMapping) Competition(providerCode string) (string, bool) {
+	code, ok := m.Competitions[providerCode]
+	return code, ok
+}
+
+// ProviderPriority задаёт порядок опроса провайдеров
+// Default - порядок для всех соревнований, Competitions - свой порядок для отдельных кодов
+type ProviderPriority struct {
+	Default      []string            `json:"default"`
+	Competitions map[string][]string `json:"competitions"`
+}
+
+// Registry объединяет провайдеров и реализует MatchApiClient, StandingsApiClient, TeamsApiClient и CompetitionsApiClient
+// Для каждого соревнования провайдеры опрашиваются по приоритету: если провайдер вернул ошибку
+// или пустой ответ, данные берутся у следующего
+type Registry struct {
+	providers map[string]Provider
+	priority  ProviderPriority
+}
+
+// Конструктор для создания реестра провайдеров
+// Если порядок по умолчанию не задан, провайдеры опрашиваются в порядке передачи
+func NewRegistry(priority ProviderPriority, providers ...Provider) (*Registry, error) {
+	r := &Registry{providers: make(map[string]Provider, len(providers)), priority: priority}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+		if len(priority.Default) == 0 {
+			r.priority.Default = append(r.priority.Default, p.Name())
+		}
+	}
+	check := func(names []string) error {
+		for _, name := range names {
+			if _, ok := r.providers[name]; !ok {
+				return fmt.Errorf("unknown provider %q", name)
+			}
+		}
+		return nil
+	}
+	if err := check(r.priority.Default); err != nil {
+		return nil, err
+	}
+	for code, names := range r.priority.Competitions {
+		if err := check(names); err != nil {
+			return nil, fmt.Errorf("priority for %s: %w", code, err)
+		}
+	}
+	return r, nil
+}
+
+// Провайдеры соревнования в порядке приоритета
+func (r *Registry) order(code string) []Provider {
+	names, ok := r.priority.Competitions[code]
+	if !ok {
+		names = r.priority.Default
+	}
+	providers := make([]Provider, 0, len(names))
+	for _, name := range names {
+		providers = append(providers, r.providers[name])
+	}
+	return providers
+}
+
+// Реализация метода FetchMatches: матчи каждого соревнования берутся у первого по приоритету
+// провайдера, который ответил без ошибки и вернул матчи этого соревнования
+func (r *Registry) FetchMatches(ctx context.Context, from, to string) ([]types.Match, error) {
+	fetched := make(map[string][]types.Match)
+	failed := make(map[string]error)
+	fetch := func(p Provider) ([]types.Match, bool) {
+		if matches, ok := fetched[p.Name()]; ok {
+			return matches, true
+		}
+		if _, ok := failed[p.Name()]; ok {
+			return nil, false
+		}
+		matches, err := p.FetchMatches(ctx, from, to)
+		if err != nil {
+			logrus.WithError(err).WithField("provider", p.Name()).Warn("Provider failed to fetch matches, falling back")
+			failed[p.Name()] = err
+			return nil, false
+		}
+		fetched[p.Name()] = matches
+		return matches, true
+	}
+
+	var result []types.Match
+	answered := false
+	for _, code := range competitionCodes() {
+		for _, p := range r.order(code) {
+			matches, ok := fetch(p)
+			if !ok {
+				continue
+			}
+			answered = true
+			if competition := matchesOf(matches, code); len(competition) > 0 {
+				result = append(result, competition...)
+				break
+			}
+		}
+	}
+	if !answered && len(failed) > 0 {
+		errs := make([]error, 0, len(failed))
+		for _, err := range failed {
+			errs = append(errs, err)
+		}
+		return nil, fmt.Errorf("all providers failed to fetch matches: %w", errors.Join(errs...))
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].UTCDate < result[j].UTCDate
+	})
+	return result, nil
+}
+
+// Реализация метода FetchStandings с переходом к следующему провайдеру при ошибке или пустой таблице
+func (r *Registry) FetchStandings(ctx context.Context, leagueCode string, season int) ([]types.Standing, error) {
+	var lastErr error
+	for _, p := range r.order(leagueCode) {
+		standings, err := p.FetchStandings(ctx, leagueCode, season)
+		if err == nil && len(standings) > 0 {
+			return standings, nil
+		}
+		if err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{"provider": p.Name(), "league": leagueCode}).Warn("Provider failed to fetch standings, falling back")
+			lastErr = err
+		}
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("no standings found for league code: %s", leagueCode)
+}
+
+// Реализация метода FetchTeams с переходом к следующему провайдеру при ошибке или пустом списке
+func (r *Registry) FetchTeams(ctx context.Context, leagueCode string) ([]types.Team, error) {
+	var lastErr error
+	for _, p := range r.order(leagueCode) {
+		teams, err := p.FetchTeams(ctx, leagueCode)
+		if err == nil && len(teams) > 0 {
+			return teams, nil
+		}
+		if err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{"provider": p.Name(), "league": leagueCode}).Warn("Provider failed to fetch teams, falling back")
+			lastErr = err
+		}
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("no teams found for league code: %s", leagueCode)
+}
+
+// Реализация метода FetchSeason с переходом к следующему провайдеру при ошибке
+func (r *Registry) FetchSeason(ctx context.Context, leagueCode string) (types.Season, error) {
+	var lastErr error
+	for _, p := range r.order(leagueCode) {
+		season, err := p.FetchSeason(ctx, leagueCode)
+		if err == nil {
+			return season, nil
+		}
+		logrus.WithError(err).WithFields(logrus.Fields{"provider": p.Name(), "league": leagueCode}).Warn("Provider failed to fetch season, falling back")
+		lastErr = err
+	}
+	if lastErr != nil {
+		return types.Season{}, lastErr
+	}
+	return types.Season{}, fmt.Errorf("no providers for league code: %s", leagueCode)
+}
+
+// Коды соревнований, которые показывает бот, в постоянном порядке
+func competitionCodes() []string {
+	codes := make([]string, 0, len(types.Leagues))
+	for _, league := range types.Leagues {
+		codes = append(codes, league.Code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Матчи одного соревнования
+func matchesOf(matches []types.Match, code string) []types.Match {
+	var result []types.Match
+	for _, m := range matches {
+		if m.Competition.Code == code {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// Файл настройки провайдеров: приоритеты и параметры дополнительных провайдеров
+type providersConfig struct {
+	Priority     ProviderPriority    `json:"priority"`
+	OpenFootball *OpenFootballConfig `json:"openfootball"`
+}
+
+// LoadRegistry собирает реестр из football-data и провайдеров из JSON-файла настроек
+// Без файла реестр состоит только из football-data
+func LoadRegistry(path string, footballData Provider) (*Registry, error) {
+	if path == "" {
+		return NewRegistry(ProviderPriority{}, footballData)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading providers config: %w", err)
+	}
+	var cfg providersConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing providers config: %w", err)
+	}
+	providers := []Provider{footballData}
+	if cfg.OpenFootball != nil {
+		openFootball, err := NewOpenFootballProvider(*cfg.OpenFootball)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, openFootball)
+	}
+	return NewRegistry(cfg.Priority, providers...)
+}
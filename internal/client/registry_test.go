@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Провайдер с заранее заданными ответами
+type fakeProvider struct {
+	name      string
+	matches   []types.Match
+	standings map[string][]types.Standing
+	err       error
+	calls     int
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) FetchMatches(ctx context.Context, from, to string) ([]types.Match, error) {
+	p.calls++
+	return p.matches, p.err
+}
+
+func (p *fakeProvider) FetchStandings(ctx context.Context, leagueCode string, season int) ([]types.Standing, error) {
+	return p.standings[leagueCode], p.err
+}
+
+func (p *fakeProvider) FetchSeason(ctx context.Context, leagueCode string) (types.Season, error) {
+	return types.Season{Code: leagueCode, StartDate: "2025-08-15"}, p.err
+}
+
+func (p *fakeProvider) FetchTeams(ctx context.Context, leagueCode string) ([]types.Team, error) {
+	return nil, p.err
+}
+
+func codeMatch(id int, code, date string) types.Match {
+	var m types.Match
+	m.ID, m.Competition.Code, m.UTCDate = id, code, date
+	return m
+}
+
+func TestRegistryFetchMatches(t *testing.T) {
+	primary := &fakeProvider{name: "primary", matches: []types.Match{
+		codeMatch(1, "PL", "2025-03-01T15:00:00Z"),
+		codeMatch(2, "FL1", "2025-03-01T20:00:00Z"),
+	}}
+	secondary := &fakeProvider{name: "secondary", matches: []types.Match{
+		codeMatch(-1, "PL", "2025-03-01T15:00:00Z"),
+		codeMatch(-2, "FL1", "2025-03-01T20:00:00Z"),
+		codeMatch(-3, "SA", "2025-03-01T18:00:00Z"),
+	}}
+	r, err := NewRegistry(ProviderPriority{Competitions: map[string][]string{"FL1": {"secondary", "primary"}}}, primary, secondary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := r.FetchMatches(context.Background(), "2025-03-01", "2025-03-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// PL от основного, SA - пробел, закрытый вторым провайдером, FL1 - второй по приоритету
+	var ids []int
+	for _, m := range matches {
+		ids = append(ids, m.ID)
+	}
+	if want := []int{1, -3, -2}; len(ids) != 3 || ids[0] != want[0] || ids[1] != want[1] || ids[2] != want[2] {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+	if primary.calls != 1 || secondary.calls != 1 {
+		t.Errorf("each provider should be asked once, got %d and %d", primary.calls, secondary.calls)
+	}
+}
+
+func TestRegistryFallsBackOnError(t *testing.T) {
+	broken := &fakeProvider{name: "broken", err: errors.New("boom")}
+	backup := &fakeProvider{name: "backup",
+		matches:   []types.Match{codeMatch(7, "PD", "2025-03-01T15:00:00Z")},
+		standings: map[string][]types.Standing{"PD": {{Position: 1}}},
+	}
+	r, err := NewRegistry(ProviderPriority{}, broken, backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matches, err := r.FetchMatches(context.Background(), "", ""); err != nil || len(matches) != 1 {
+		t.Errorf("FetchMatches = %v, %v; want the backup match", matches, err)
+	}
+	if standings, err := r.FetchStandings(context.Background(), "PD", 2025); err != nil || len(standings) != 1 {
+		t.Errorf("FetchStandings = %v, %v; want the backup table", standings, err)
+	}
+	if season, err := r.FetchSeason(context.Background(), "PD"); err != nil || season.Year() != 2025 {
+		t.Errorf("FetchSeason = %+v, %v; want the backup season", season, err)
+	}
+
+	only, _ := NewRegistry(ProviderPriority{}, broken)
+	if _, err := only.FetchMatches(context.Background(), "", ""); err == nil {
+		t.Error("expected an error when every provider fails")
+	}
+	if _, err := NewRegistry(ProviderPriority{Default: []string{"missing"}}, broken); err == nil {
+		t.Error("expected an error for an unknown provider in the priority")
+	}
+}
+
+func TestLoadRegistryExample(t *testing.T) {
+	r, err := LoadRegistry(filepath.Join("..", "..", "configs", "providers.example.json"), NewFootballAPIClient(nil, "", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.order("FL1"); len(got) != 2 || got[0].Name() != OpenFootballProviderName {
+		t.Errorf("FL1 should prefer openfootball, got %v", got)
+	}
+	if got := r.order("PL"); len(got) != 2 || got[0].Name() != FootballDataProvider {
+		t.Errorf("PL should prefer football-data, got %v", got)
+	}
+}
+
+func TestOpenFootballProvider(t *testing.T) {
+	dir := t.TempDir()
+	file := `{"name": "Premier League 2024/25", "matches": [
+		{"round": "Matchday 1", "date": "2024-08-16", "time": "20:00", "team1": "Manchester United FC", "team2": "Fulham FC", "score": {"ht": [0, 0], "ft": [1, 0]}},
+		{"round": "Matchday 1", "date": "2024-08-17", "time": "12:30", "team1": "Ipswich Town FC", "team2": "Liverpool FC", "score": {"ht": [0, 0], "ft": [0, 2]}},
+		{"round": "Matchday 1", "date": "2024-08-17", "time": "15:00", "team1": "Unknown FC", "team2": "Fulham FC"},
+		{"round": "Matchday 2", "date": "2024-08-24", "time": "15:00", "team1": "Fulham FC", "team2": "Liverpool FC"}
+	]}`
+	if err := os.WriteFile(filepath.Join(dir, "en.1.json"), []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewOpenFootballProvider(OpenFootballConfig{
+		Dir: dir,
+		IDMapping: IDMapping{
+			Competitions: map[string]string{"en.1": "PL"},
+			Teams:        map[string]int{"Manchester United FC": 66, "Fulham FC": 63, "Ipswich Town FC": 349, "Liverpool FC": 64},
+		},
+		TimeZones: map[string]string{"en.1": "Europe/London"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches, err := p.FetchMatches(context.Background(), "2024-08-16", "2024-08-17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want 2 mapped matches in range", len(matches))
+	}
+	first := matches[0]
+	if first.UTCDate != "2024-08-16T19:00:00Z" || first.HomeTeam.ID != 66 || first.Competition.Name != "EPL" ||
+		first.Status != "FINISHED" || first.Score.Winner != "HOME_TEAM" || first.Score.HalfTime == nil || first.ID >= 0 {
+		t.Errorf("unexpected match %+v", first)
+	}
+
+	standings, err := p.FetchStandings(context.Background(), "PL", 2024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(standings) != 4 || standings[0].Team.ID != 64 || standings[0].Points != 3 || standings[3].Team.ID != 349 {
+		t.Errorf("unexpected standings %+v", standings)
+	}
+	if _, err := p.FetchStandings(context.Background(), "PD", 0); err == nil {
+		t.Error("expected an error for a league without a file")
+	}
+	if _, err := p.FetchStandings(context.Background(), "PL", 2025); err == nil {
+		t.Error("expected an error for a season the file does not hold")
+	}
+	season, err := p.FetchSeason(context.Background(), "PL")
+	if err != nil || season.StartDate != "2024-08-16" || season.EndDate != "2024-08-24" || season.CurrentMatchday != 1 {
+		t.Errorf("FetchSeason = %+v, %v; want 2024-08-16 - 2024-08-24 at matchday 1", season, err)
+	}
+}
@@ -0,0 +1,227 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Лимит бесплатного тарифа football-data: 10 запросов в минуту
+	FreeTierRequestsPerMinute = 10
+	// Таймаут одного запроса к API
+	requestTimeout = 30 * time.Second
+	// Сколько раз повторяется запрос после 429 или ошибки сервера
+	maxRetries = 4
+	// Первая пауза перед повтором; дальше она удваивается
+	retryBackoff = 2 * time.Second
+	// Больше этой паузы между повторами не ждём
+	maxRetryBackoff = time.Minute
+)
+
+// Заголовки football-data с остатком запросов и временем до сброса счётчика в секундах
+const (
+	headerRequestsAvailable = "X-Requests-Available-Minute"
+	headerCounterReset      = "X-RequestCounter-Reset"
+)
+
+// APIError описывает ответ API с неуспешным статусом
+// Вызывающий код может достать её через errors.As и проверить статус
+type APIError struct {
+	StatusCode int
+	URL        string
+	Body       string
+	// Через сколько API обещает сбросить счётчик запросов (для 429)
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request %s failed with status code: %d, response: %s", e.URL, e.StatusCode, e.Body)
+}
+
+// RateLimited сообщает, что лимит запросов исчерпан
+func (e *APIError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// Retryable сообщает, что запрос имеет смысл повторить позже
+func (e *APIError) Retryable() bool {
+	return e.RateLimited() || e.StatusCode >= http.StatusInternalServerError
+}
+
+// IsRateLimited проверяет, что ошибка вызвана исчерпанным лимитом запросов
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.RateLimited()
+}
+
+// Конструктор http.Client с таймаутом для запросов к API
+func NewHTTPClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
+
+// RateLimiter - корзина токенов, общая для всех запросов процесса к API
+// Токены пополняются равномерно; если API сообщил, что счётчик исчерпан,
+// запросы ждут его сброса
+type RateLimiter struct {
+	mu          sync.Mutex
+	capacity    float64
+	tokens      float64
+	perSecond   float64
+	updated     time.Time
+	pausedUntil time.Time
+	now         func() time.Time
+}
+
+// Конструктор для создания корзины на perMinute запросов в минуту
+func NewRateLimiter(perMinute int) *RateLimiter {
+	return &RateLimiter{
+		capacity:  float64(perMinute),
+		tokens:    float64(perMinute),
+		perSecond: float64(perMinute) / 60,
+		updated:   time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Wait блокируется, пока не освободится токен или не отменится контекст
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Pause останавливает запросы на d: так учитывается сброс счётчика, о котором сообщил API
+func (l *RateLimiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until := l.now().Add(d)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.tokens = 0
+}
+
+// Забирает токен и возвращает 0 или сообщает, сколько ждать до следующей попытки
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if !l.pausedUntil.IsZero() {
+		// После сброса счётчика доступен весь лимит
+		l.tokens, l.pausedUntil = l.capacity, time.Time{}
+	} else {
+		l.tokens += now.Sub(l.updated).Seconds() * l.perSecond
+		if l.tokens > l.capacity {
+			l.tokens = l.capacity
+		}
+	}
+	l.updated = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.perSecond * float64(time.Second))
+}
+
+// Выполняет GET-запрос к API с учётом лимита и повторами
+// Возвращает тело успешного ответа или ошибку; неуспешный статус возвращается как *APIError
+func (m *FootballAPIClient) get(ctx context.Context, url string) ([]byte, error) {
+	backoff := m.backoff
+	for attempt := 0; ; attempt++ {
+		if err := m.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		body, err := m.do(ctx, url)
+		if err == nil {
+			if m.recordDir != "" {
+				m.record(url, body)
+			}
+			return body, nil
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !apiErr.Retryable() || attempt >= m.maxRetries {
+			return nil, err
+		}
+		delay := backoff
+		if apiErr.RetryAfter > delay {
+			delay = apiErr.RetryAfter
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// Один запрос к API; по заголовкам ответа обновляет лимит
+func (m *FootballAPIClient) do(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Add("X-Auth-Token", m.apiKey)
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	reset := counterReset(resp.Header)
+	exhausted := resp.Header.Get(headerRequestsAvailable) == "0" || resp.StatusCode == http.StatusTooManyRequests
+	if exhausted && reset > 0 {
+		m.limiter.Pause(reset)
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{StatusCode: resp.StatusCode, URL: url, Body: string(body)}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			apiErr.RetryAfter = reset
+		}
+		return nil, apiErr
+	}
+	return body, nil
+}
+
+// Время до сброса счётчика запросов из заголовка X-RequestCounter-Reset
+func counterReset(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get(headerCounterReset))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Пауза, которую прерывает отмена контекста
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Клиент без пауз между повторами, чтобы тесты не ждали
+func testClient(server *httptest.Server) *FootballAPIClient {
+	c := NewFootballAPIClient(server.Client(), "key", NewRateLimiter(600))
+	c.baseURL = server.URL
+	c.backoff = time.Millisecond
+	return c
+}
+
+func TestGetRetriesRateLimitedAndServerErrors(t *testing.T) {
+	statuses := []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusOK}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "key" {
+			t.Errorf("missing auth token")
+		}
+		w.WriteHeader(statuses[calls])
+		w.Write([]byte(`{"matches":[]}`))
+		calls++
+	}))
+	defer server.Close()
+
+	body, err := testClient(server).get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if calls != 3 || string(body) != `{"matches":[]}` {
+		t.Errorf("calls = %d, body = %s; want 3 calls and the final body", calls, body)
+	}
+}
+
+func TestGetReturnsTypedError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := testClient(server)
+	c.maxRetries = 1
+	_, err := c.get(context.Background(), server.URL)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || !IsRateLimited(err) {
+		t.Fatalf("err = %v, want a rate limited *APIError", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want the first attempt and one retry", calls)
+	}
+
+	calls = 0
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusForbidden)
+	})
+	if _, err := c.get(context.Background(), server.URL); IsRateLimited(err) || !errors.As(err, &apiErr) || apiErr.Retryable() {
+		t.Errorf("err = %v, want a non-retryable *APIError", err)
+	}
+	if calls != 1 {
+		t.Errorf("a 403 should not be retried, calls = %d", calls)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(10)
+	l.now = func() time.Time { return now }
+	l.updated = now
+
+	for i := 0; i < 10; i++ {
+		if d := l.reserve(); d != 0 {
+			t.Fatalf("request %d waited %v within the budget", i+1, d)
+		}
+	}
+	if d := l.reserve(); d != 6*time.Second {
+		t.Errorf("the 11th request should wait for one token (6s), got %v", d)
+	}
+
+	now = now.Add(6 * time.Second)
+	if d := l.reserve(); d != 0 {
+		t.Errorf("a refilled token should be available, got wait %v", d)
+	}
+
+	// Сброс счётчика от API важнее корзины
+	l.Pause(40 * time.Second)
+	if d := l.reserve(); d != 40*time.Second {
+		t.Errorf("paused limiter should wait for the counter reset, got %v", d)
+	}
+	now = now.Add(40 * time.Second)
+	for i := 0; i < 10; i++ {
+		if d := l.reserve(); d != 0 {
+			t.Fatalf("after the reset request %d waited %v", i+1, d)
+		}
+	}
+}
+
+func TestCounterReset(t *testing.T) {
+	header := http.Header{}
+	header.Set(headerCounterReset, "17")
+	if got := counterReset(header); got != 17*time.Second {
+		t.Errorf("counterReset = %v, want 17s", got)
+	}
+	if got := counterReset(http.Header{}); got != 0 {
+		t.Errorf("counterReset without header = %v, want 0", got)
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -20,8 +22,33 @@ type Config struct {
 	PostgresHost       string
 	PostgresPort       string
 	RedisURL           string
+	// Telegram ID администраторов, которым доступны служебные команды
+	AdminIDs []int64
+	// Чат, в который отправляются отчёты о внутренних ошибках бота; 0 - только логи
+	AdminChatID int64
+	// Путь к JSON-файлу профиля рейтинга матчей; пусто - встроенный профиль
+	RatingProfilePath string
+	// Название стратегии рейтинга матчей (heuristic, balance, ...); пусто - heuristic
+	RatingStrategy string
+	// Путь к JSON-файлу провайдеров данных и их приоритетов; пусто - только football-data
+	ProvidersPath string
+	// Каталог записанных ответов football-data: бот и обновление работают без сети и ключа
+	FootballDataReplayDir string
+	// Каталог, куда сохраняются ответы football-data для последующего воспроизведения
+	FootballDataRecordDir string
+	// Доли лимита football-data в запросах в минуту: у сервиса обновления (и сидеров) и у бота
+	// свои корзины, поэтому в сумме доли не должны превышать лимит тарифа
+	UpdaterRequestsPerMinute int
+	BotRequestsPerMinute     int
 }
 
+// Доли по умолчанию делят бесплатный тариф football-data (10 запросов в минуту):
+// обновлению нужно больше, бот обращается к API редко
+const (
+	DefaultUpdaterRequestsPerMinute = 7
+	DefaultBotRequestsPerMinute     = 3
+)
+
 // LoadConfig функция для загрузки конфигурации из .env файла
 // Использует пакет godotenv для чтения переменных окружения
 // Возвращает указатель на Config с заполненными полями
@@ -33,14 +60,76 @@ func LoadConfig() *Config {
 	}
 
 	return &Config{
-		TelegramToken:      os.Getenv("TELEGRAM_BOT_API_KEY"),
-		FootballDataAPIKey: os.Getenv("FOOTBALL_DATA_API_KEY"),
-		MongoURI:           os.Getenv("MONGODB_URI"),
-		PostgresUser:       os.Getenv("PG_USER"),
-		PostgresPass:       os.Getenv("PG_PASSWORD"),
-		PostgresDB:         os.Getenv("PG_DB"),
-		PostgresHost:       os.Getenv("PG_HOST"),
-		PostgresPort:       os.Getenv("PG_PORT"),
-		RedisURL:           os.Getenv("REDIS_URL"),
+		TelegramToken:         os.Getenv("TELEGRAM_BOT_API_KEY"),
+		FootballDataAPIKey:    os.Getenv("FOOTBALL_DATA_API_KEY"),
+		MongoURI:              os.Getenv("MONGODB_URI"),
+		PostgresUser:          os.Getenv("PG_USER"),
+		PostgresPass:          os.Getenv("PG_PASSWORD"),
+		PostgresDB:            os.Getenv("PG_DB"),
+		PostgresHost:          os.Getenv("PG_HOST"),
+		PostgresPort:          os.Getenv("PG_PORT"),
+		RedisURL:              os.Getenv("REDIS_URL"),
+		AdminIDs:              parseIDs(os.Getenv("ADMIN_IDS")),
+		AdminChatID:           parseID(os.Getenv("ADMIN_CHAT_ID")),
+		RatingProfilePath:     os.Getenv("RATING_PROFILE_PATH"),
+		RatingStrategy:        os.Getenv("RATING_STRATEGY"),
+		ProvidersPath:         os.Getenv("PROVIDERS_PATH"),
+		FootballDataReplayDir: os.Getenv("FOOTBALL_DATA_REPLAY_DIR"),
+		FootballDataRecordDir: os.Getenv("FOOTBALL_DATA_RECORD_DIR"),
+
+		UpdaterRequestsPerMinute: parseRate(os.Getenv("FOOTBALL_DATA_UPDATER_RPM"), DefaultUpdaterRequestsPerMinute),
+		BotRequestsPerMinute:     parseRate(os.Getenv("FOOTBALL_DATA_BOT_RPM"), DefaultBotRequestsPerMinute),
+	}
+}
+
+// IsAdmin проверяет, есть ли Telegram ID в списке администраторов
+func (c *Config) IsAdmin(telegramID int64) bool {
+	for _, id := range c.AdminIDs {
+		if id == telegramID {
+			return true
+		}
+	}
+	return false
+}
+
+// parseID разбирает один ID; пустое или некорректное значение даёт 0
+func parseID(value string) int64 {
+	ids := parseIDs(value)
+	if len(ids) == 0 {
+		return 0
+	}
+	return ids[0]
+}
+
+// parseRate разбирает число запросов в минуту; пустое или некорректное значение даёт fallback
+func parseRate(value string, fallback int) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback
+	}
+	rate, err := strconv.Atoi(value)
+	if err != nil || rate < 1 {
+		log.Printf("Invalid requests per minute %q, using %d", value, fallback)
+		return fallback
+	}
+	return rate
+}
+
+// parseIDs разбирает список ID через запятую
+// Некорректные значения пропускаются с записью в лог
+func parseIDs(value string) []int64 {
+	var ids []int64
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			log.Printf("Skipping invalid ID %q: %v", part, err)
+			continue
+		}
+		ids = append(ids, id)
 	}
+	return ids
 }
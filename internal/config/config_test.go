@@ -0,0 +1,11 @@
+package config
+
+import "testing"
+
+func TestParseRate(t *testing.T) {
+	for value, want := range map[string]int{"": 7, "4": 4, " 5 ": 5, "abc": 7, "0": 7} {
+		if got := parseRate(value, 7); got != want {
+			t.Errorf("parseRate(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
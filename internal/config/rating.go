@@ -0,0 +1,27 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// LoadRatingProfile читает профиль рейтинга из JSON-файла и проверяет его
+// Возвращает ошибку, если файл не читается, не разбирается или профиль некорректен
+func LoadRatingProfile(path string) (*types.RatingProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rating profile %s: %w", path, err)
+	}
+
+	var profile types.RatingProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("error parsing rating profile %s: %w", path, err)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rating profile %s: %w", path, err)
+	}
+	return &profile, nil
+}
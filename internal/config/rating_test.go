@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Профиль в репозитории должен совпадать со встроенным, чтобы файл и код не расходились
+func TestShippedRatingProfileMatchesDefault(t *testing.T) {
+	profile, err := LoadRatingProfile("../../configs/rating_profile.json")
+	if err != nil {
+		t.Fatalf("LoadRatingProfile returned an error: %v", err)
+	}
+	if want := types.DefaultRatingProfile(); !reflect.DeepEqual(*profile, want) {
+		t.Errorf("configs/rating_profile.json differs from types.DefaultRatingProfile()")
+	}
+}
+
+func TestLoadRatingProfileRejectsInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.json")
+	os.WriteFile(path, []byte(`{"name": "broken", "version": 2, "weights": {"position": 2}, "minRating": 0.5, "maxRating": 0.4}`), 0644)
+	if _, err := LoadRatingProfile(path); err == nil {
+		t.Fatal("expected a validation error")
+	}
+}
+
+// Изменённые веса без новой версии должны давать другую версию стратегии
+func TestRatingProfileChecksumTracksContents(t *testing.T) {
+	profile := types.DefaultRatingProfile()
+	if profile.Checksum() != types.DefaultRatingProfile().Checksum() {
+		t.Fatal("checksum of the same profile should be stable")
+	}
+	edited := types.DefaultRatingProfile()
+	edited.StakesBonus += 0.1
+	if edited.Checksum() == profile.Checksum() {
+		t.Error("checksum should change when weights change and the version stays the same")
+	}
+}
@@ -36,13 +36,19 @@ func ConnectToMongoDB(uri string) (*mongo.Client, error) {
 	if err := createMatchesIndexes(client, "football"); err != nil {
 		return nil, fmt.Errorf("failed to create indexes: %w", err)
 	}
+	if err := createRivalriesIndexes(client, "football"); err != nil {
+		return nil, fmt.Errorf("failed to create indexes: %w", err)
+	}
+	if err := createRatingHistoryIndexes(client, "football"); err != nil {
+		return nil, fmt.Errorf("failed to create indexes: %w", err)
+	}
 
 	logrus.Info("Connected to MongoDB")
 	return client, nil
 }
 
 // createMatchesIndexes создает индекс на коллекции матчей в MongoDB
-// Индекс включает поля homeTeam.id, awayTeam.id и date для ускорения запросов
+// Индекс включает поля hometeam.id, awayteam.id и utcdate для ускорения запросов
 // Принимает указатель на mongo.Client и имя базы данных
 // Возвращает ошибку, если не удалось создать индекс
 // Использует контекст с таймаутом 10 секунд для создания индекса
@@ -50,11 +56,11 @@ func createMatchesIndexes(client *mongo.Client, dbName string) error {
 	collection := client.Database(dbName).Collection("matches")
 	index := mongo.IndexModel{
 		Keys: bson.D{
-			{Key: "homeTeam.id", Value: 1},
-			{Key: "awayTeam.id", Value: 1},
-			{Key: "date", Value: -1},
+			{Key: "hometeam.id", Value: 1},
+			{Key: "awayteam.id", Value: 1},
+			{Key: "utcdate", Value: -1},
 		},
-		Options: options.Index().SetName("homeTeam.id_1_awayTeam.id_1_date_-1"),
+		Options: options.Index().SetName("hometeam.id_1_awayteam.id_1_utcdate_-1"),
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -66,6 +72,42 @@ func createMatchesIndexes(client *mongo.Client, dbName string) error {
 	return nil
 }
 
+// createRivalriesIndexes создает уникальный индекс на паре команд в коллекции дерби
+func createRivalriesIndexes(client *mongo.Client, dbName string) error {
+	collection := client.Database(dbName).Collection("rivalries")
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "teama", Value: 1},
+			{Key: "teamb", Value: 1},
+		},
+		Options: options.Index().SetName("teama_1_teamb_1").SetUnique(true),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := collection.Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create index on rivalries: %w", err)
+	}
+	return nil
+}
+
+// createRatingHistoryIndexes создает индекс для выборки истории рейтинга матча по времени
+func createRatingHistoryIndexes(client *mongo.Client, dbName string) error {
+	collection := client.Database(dbName).Collection("rating_history")
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "matchid", Value: 1},
+			{Key: "computedat", Value: -1},
+		},
+		Options: options.Index().SetName("matchid_1_computedat_-1"),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := collection.Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create index on rating history: %w", err)
+	}
+	return nil
+}
+
 // ConnectToPostgres создает подключение к PostgreSQL и возвращает указатель на sql.DB
 // Принимает параметры подключения: пользователь, пароль, имя базы данных, хост и порт
 func ConnectToPostgres(user, password, dbname, host, port string) (*sql.DB, error) {
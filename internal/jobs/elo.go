@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-co-op/gocron"
+	"github.com/sirupsen/logrus"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+)
+
+// Функция, которая обновляет рейтинги Эло команд в фоне
+// Каждый час учитывает завершённые матчи, появившиеся после прошлого запуска
+// При первом запуске прогоняет всю историю матчей
+func RegisterEloJob(s *gocron.Scheduler, eloService *service.EloService) {
+	logrus.Info("registering elo")
+	_, err := s.Every(1).Hour().Do(func() {
+		if _, err := eloService.HandleUpdate(context.Background()); err != nil {
+			log.Printf("Failed to update elo: %v", err)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule elo job: %v", err)
+	}
+}
@@ -2,7 +2,6 @@ package jobs
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"time"
 
@@ -16,9 +15,12 @@ import (
 // Функция, которая апдейтит матче в фоне, пока работает бот
 // Используется gocron для планирования задач
 // Каждые 24 часа выполняет обновление матчей
-// Получает матчи из API, рассчитывает рейтинг и сохраняет в базу данных
-// Очищает кэш Redis для топовых матчей и всех матчей после обновления
-func RegisterMatchesJob(s *gocron.Scheduler, service *service.MatchesService, redisClient *cache.RedisClient, apiService client.MatchApiClient, calculator service.Calculator) {
+// Забирает и вчерашний день, чтобы у сыгранных матчей появились итоговые счёт и статус;
+// итоги сохраняются всегда, а их предматчевый рейтинг не трогается
+// Рейтинг рассчитывается и сохраняется только для не начавшихся матчей
+// Пересчитывает ближайшие матчи команд, сыгравших за вчера: у них изменилась форма
+// Очищает кэш Redis для персональных топов и всех матчей после обновления
+func RegisterMatchesJob(s *gocron.Scheduler, matchesService *service.MatchesService, redisClient *cache.RedisClient, apiService client.MatchApiClient, calculator service.Calculator) {
 	logrus.Info("registering matches")
 	_, err := s.Every(24).Hours().Do(func() {
 
@@ -26,8 +28,8 @@ func RegisterMatchesJob(s *gocron.Scheduler, service *service.MatchesService, re
 		ctx := context.Background()
 		start := time.Now()
 
-		from := time.Now()
-		to := from.Add(24 * time.Hour)
+		from := time.Now().Add(-24 * time.Hour)
+		to := time.Now().Add(24 * time.Hour)
 		matches, err := apiService.FetchMatches(ctx, from.Format("2006-01-02"), to.Format("2006-01-02"))
 		if err != nil {
 			log.Printf("Failed to fetch matches: %v", err)
@@ -35,22 +37,34 @@ func RegisterMatchesJob(s *gocron.Scheduler, service *service.MatchesService, re
 		}
 		log.Printf("Fetched %d matches", len(matches))
 
-		for _, match := range matches {
-			fmt.Println(match.HomeTeam.Name + " vs " + match.AwayTeam.Name)
-			rating, err := service.CalculateRatingOfMatch(ctx, match, calculator)
-			if err != nil {
-				logrus.Warnf("Error calculating rating for match %v vs %v; error: %v; skipping", match.HomeTeam.Name, match.AwayTeam.Name, err)
-				continue
+		saved, err := matchesService.HandleSaveResults(ctx, matches)
+		if err != nil {
+			log.Printf("Failed to save match results: %v", err)
+		}
+		log.Printf("Saved results of %d started matches", saved)
 
+		results, err := matchesService.CalculateRatingsOfMatches(ctx, service.UpcomingMatches(matches), calculator)
+		if err != nil {
+			log.Printf("Failed to calculate ratings: %v", err)
+			return
+		}
+		for _, result := range results {
+			if result.Err != nil {
+				match := result.Match
+				logrus.Warnf("Error calculating rating for match %v vs %v; error: %v; skipping", match.HomeTeam.Name, match.AwayTeam.Name, result.Err)
 			}
-			match.Rating = rating
-			err = service.HandleUpsertMatch(ctx, match)
-			if err != nil {
-				log.Printf("Failed to upsert match: %v", err)
-			}
 		}
-		//Очищаем буфер изобрадений
-		if err := redisClient.DeleteByPattern(ctx, "top_matches_image"); err != nil {
+		if _, err := matchesService.HandleSaveRatings(ctx, results, service.RatingReasonSchedule); err != nil {
+			log.Printf("Failed to save ratings: %v", err)
+		}
+		// У команд, сыгравших матч, изменилась форма: пересчитываем их ближайшие матчи
+		recomputed, err := matchesService.HandleRecomputeForTeams(ctx, service.FinishedTeams(matches), calculator, service.RatingReasonForm)
+		if err != nil {
+			log.Printf("Failed to recompute ratings after results: %v", err)
+		}
+		log.Printf("Recomputed ratings of %d upcoming matches after results", recomputed)
+		// Сбрасываем персональные топы и изображения расписаний
+		if err := redisClient.DeleteByPattern(ctx, service.PersonalTopCachePrefix+"*"); err != nil {
 			log.Printf("Failed to delete top matches: %v", err)
 		}
 		if err := redisClient.DeleteByPattern(ctx, "all_matches*"); err != nil {
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+)
+
+// Функция, которая следит за файлом профиля рейтинга, пока не отменён ctx
+// Раз в interval проверяет, изменился ли файл, и подхватывает новые веса без перезапуска
+// Работает на своём тикере, а не в планировщике: частая проверка не должна занимать
+// место долгих задач обновления, которые планировщик выполняет по одной
+// Новые веса применяются при следующем обновлении матчей
+func WatchRatingProfile(ctx context.Context, profiles *service.RatingProfiles, interval time.Duration) {
+	logrus.Info("watching rating profile")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ReloadRatingProfile(profiles)
+		}
+	}
+}
+
+// ReloadRatingProfile перечитывает профиль рейтинга и пишет результат в лог
+// Вызывается по тикеру и по сигналу SIGHUP
+func ReloadRatingProfile(profiles *service.RatingProfiles) {
+	reloaded, err := profiles.Reload()
+	if err != nil {
+		log.Printf("Failed to reload rating profile, keeping the previous one: %v", err)
+		return
+	}
+	if reloaded {
+		profile := profiles.Current()
+		log.Printf("Loaded rating profile %s v%d (%s)", profile.Name, profile.Version, profile.Checksum())
+	}
+}
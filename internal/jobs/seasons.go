@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/sirupsen/logrus"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+)
+
+// Функция, которая раз в сутки обновляет текущие сезоны лиг
+// Летом API переходит на новый сезон, и таблицы следуют за ним без правки кода
+// Первый запуск - через сутки: при старте сезоны обновляет RefreshSeasons до запуска планировщика
+func RegisterSeasonsJob(s *gocron.Scheduler, seasonService *service.SeasonService) {
+	logrus.Info("registering seasons")
+	_, err := s.Every(24).Hours().WaitForSchedule().Do(func() {
+		RefreshSeasons(context.Background(), seasonService)
+	})
+
+	if err != nil {
+		log.Fatalf("Failed to schedule seasons job: %v", err)
+	}
+}
+
+// RefreshSeasons обновляет сезоны лиг из API и пишет результат в лог
+// Вызывается по расписанию и при старте сервиса обновления, чтобы таблицы сразу загружались за актуальный сезон
+func RefreshSeasons(ctx context.Context, seasonService *service.SeasonService) {
+	log.Println("Starting seasons update...")
+	start := time.Now()
+
+	seasons, err := seasonService.HandleRefreshSeasons(ctx)
+	if err != nil {
+		log.Printf("Failed to update some seasons: %v", err)
+	}
+	for _, season := range seasons {
+		log.Printf("Season of %s: %s - %s, matchday %d", season.League, season.StartDate, season.EndDate, season.CurrentMatchday)
+	}
+
+	log.Printf("seasons update completed in %v", time.Since(start))
+}
@@ -17,33 +17,52 @@ import (
 // Функция, которая апдейтит турнирные таблицы в фоне, пока работает бот
 // Используется gocron для планирования задач
 // Каждые 6 часов выполняет обновление турнирных таблиц
-// Получает таблицы из API и сохраняет в базу данных
-// Очищает кэш Redis для изображений таблиц после обновления
-func RegisterStandingsJob(s *gocron.Scheduler, service *service.StandingsService, redisClient *cache.RedisClient, apiService client.StandingsApiClient) {
+// Получает таблицы активного сезона из API и сохраняет в базу данных
+// Пересчитывает рейтинги ближайших матчей команд, чьё место или очки изменились
+// Очищает кэш Redis для изображений таблиц и, если рейтинги изменились, для персональных топов
+func RegisterStandingsJob(s *gocron.Scheduler, standingsService *service.StandingsService, matchesService *service.MatchesService, seasonService *service.SeasonService, redisClient *cache.RedisClient, apiService client.StandingsApiClient, calculator service.Calculator) {
 	logrus.Info("registering standings")
 	ctx := context.Background()
 	_, err := s.Every(6).Hours().Do(func() {
 		log.Println("Starting standings update...")
 		start := time.Now()
 
+		var changed []int
 		for leagueName, league := range types.Leagues {
 
-			standings, err := apiService.FetchStandings(ctx, league.Code)
+			season, err := seasonService.HandleGetActiveSeason(ctx, league.Code)
+			if err != nil {
+				log.Printf("Failed to get season for %s: %v", leagueName, err)
+				continue
+			}
+			standings, err := apiService.FetchStandings(ctx, league.Code, season)
 			if err != nil {
 				log.Printf("Failed to fetch standings for %s: %v", leagueName, err)
 				continue
 			}
 
-			if err := service.HandleSaveStandings(context.Background(), league.CollectionName, standings); err != nil {
+			teams, err := standingsService.HandleSaveStandings(context.Background(), league.CollectionName, standings)
+			if err != nil {
 				log.Printf("Failed to save standings for %s: %v", leagueName, err)
-			} else {
-				log.Printf("Updated standings for %s (%d records)", leagueName, len(standings))
+				continue
 			}
+			changed = append(changed, teams...)
+			log.Printf("Updated standings for %s (%d records, %d changed)", leagueName, len(standings), len(teams))
 		}
-		//Очищаем буфер изобрадений)
 
-		err := redisClient.DeleteByPattern(ctx, "table_image:*")
+		recomputed, err := matchesService.HandleRecomputeForTeams(ctx, changed, calculator, service.RatingReasonStandings)
 		if err != nil {
+			log.Printf("Failed to recompute ratings after standings update: %v", err)
+		}
+		if recomputed > 0 {
+			log.Printf("Recomputed ratings of %d upcoming matches", recomputed)
+			if err := redisClient.DeleteByPattern(ctx, service.PersonalTopCachePrefix+"*"); err != nil {
+				log.Printf("Failed to delete top matches: %v", err)
+			}
+		}
+		//Очищаем буфер изобрадений)
+
+		if err := redisClient.DeleteByPattern(ctx, "table_image:*"); err != nil {
 			log.Printf("failed to delete table images: %s", err)
 		}
 
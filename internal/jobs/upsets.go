@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"log"
+
+	"github.com/go-co-op/gocron"
+	"github.com/sirupsen/logrus"
+	"github.com/vsespontanno/tgbot_fschedule/internal/bot/handlers"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Функция, которая рассылает уведомления о сенсациях, пока работает бот
+// Каждые 30 минут ищет сенсации среди недавно сыгранных матчей
+// и отправляет каждую один раз подписчикам лиги
+func RegisterUpsetAlertsJob(s *gocron.Scheduler, upsetService *service.UpsetService, bot *tgbotapi.BotAPI) {
+	logrus.Info("registering upset alerts")
+	_, err := s.Every(30).Minutes().Do(func() {
+		alerts, err := upsetService.HandlePendingAlerts(context.Background())
+		if err != nil {
+			log.Printf("Failed to find upsets: %v", err)
+		}
+		if len(alerts) == 0 {
+			return
+		}
+		sent := handlers.SendUpsetAlerts(bot, alerts)
+		log.Printf("Sent %d upset alerts for %d matches", sent, len(alerts))
+	})
+	if err != nil {
+		log.Fatalf("Failed to schedule upset alerts job: %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS events (
+    id BIGSERIAL PRIMARY KEY,
+    telegram_id BIGINT NOT NULL,
+    chat_type VARCHAR(32),
+    kind VARCHAR(16) NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    league VARCHAR(64),
+    latency_ms INTEGER NOT NULL,
+    outcome VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS events_created_at_idx ON events (created_at);
+CREATE INDEX IF NOT EXISTS events_telegram_id_idx ON events (telegram_id);
+-- +goose Down
+DROP TABLE IF EXISTS events;
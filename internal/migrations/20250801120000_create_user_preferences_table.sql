@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS user_preferences (
+    id BIGSERIAL PRIMARY KEY,
+    telegram_id BIGINT NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    value VARCHAR(64) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    mode VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (telegram_id, kind, value)
+);
+-- +goose Down
+DROP TABLE IF EXISTS user_preferences;
@@ -0,0 +1,8 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS upset_alerts (
+    match_id INTEGER PRIMARY KEY,
+    surprise DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose Down
+DROP TABLE IF EXISTS upset_alerts;
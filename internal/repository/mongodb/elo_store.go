@@ -0,0 +1,153 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Идентификатор документа с прогрессом пересчёта в коллекции состояния
+const eloStateID = "elo"
+
+// Интерфейс для взаимодействия с рейтингами Эло команд
+type EloStore interface {
+	GetTeamElo(ctx context.Context, teamID int) (*types.TeamElo, error)
+	GetTeamElos(ctx context.Context) ([]types.TeamElo, error)
+	SaveTeamElos(ctx context.Context, elos []types.TeamElo) error
+	GetEloState(ctx context.Context) (types.EloState, error)
+	SaveEloState(ctx context.Context, state types.EloState) error
+	ResetElo(ctx context.Context) error
+}
+
+// Интерфейс для получения рейтинга Эло в контексте калькуляции рейтинга матчей
+type EloCalcStore interface {
+	GetTeamElo(ctx context.Context, teamID int) (*types.TeamElo, error)
+	GetTeamElosByIDs(ctx context.Context, teamIDs []int) ([]types.TeamElo, error)
+}
+
+// Структура для хранения рейтингов Эло: по документу на команду и отдельный документ состояния
+type MongoDBEloStore struct {
+	dbName        string
+	client        *mongo.Client
+	collName      string
+	stateCollName string
+}
+
+// Конструктор структуры для взаимодействия с рейтингами Эло
+func NewMongoDBEloStore(client *mongo.Client, dbName string) *MongoDBEloStore {
+	return &MongoDBEloStore{
+		client:        client,
+		dbName:        dbName,
+		collName:      "elo",
+		stateCollName: "elo_state",
+	}
+}
+
+// Метод для получения рейтинга команды; возвращает nil, если команда ещё не играла
+func (m *MongoDBEloStore) GetTeamElo(ctx context.Context, teamID int) (*types.TeamElo, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	var elo types.TeamElo
+	err := coll.FindOne(ctx, bson.M{"teamid": teamID}).Decode(&elo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding elo for team %d: %w", teamID, err)
+	}
+	return &elo, nil
+}
+
+// Метод для получения рейтингов всех команд
+func (m *MongoDBEloStore) GetTeamElos(ctx context.Context) ([]types.TeamElo, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	cur, err := coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("error finding team elos: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var elos []types.TeamElo
+	if err := cur.All(ctx, &elos); err != nil {
+		return nil, fmt.Errorf("error decoding team elos: %w", err)
+	}
+	return elos, nil
+}
+
+// Метод для получения текущих рейтингов нескольких команд одним запросом, без истории
+func (m *MongoDBEloStore) GetTeamElosByIDs(ctx context.Context, teamIDs []int) ([]types.TeamElo, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	opts := options.Find().SetProjection(bson.M{"history": 0})
+	cur, err := coll.Find(ctx, bson.M{"teamid": bson.M{"$in": teamIDs}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding team elos: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var elos []types.TeamElo
+	if err := cur.All(ctx, &elos); err != nil {
+		return nil, fmt.Errorf("error decoding team elos: %w", err)
+	}
+	return elos, nil
+}
+
+// Метод для сохранения рейтингов команд вместе с историей
+func (m *MongoDBEloStore) SaveTeamElos(ctx context.Context, elos []types.TeamElo) error {
+	if len(elos) == 0 {
+		return nil
+	}
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	models := make([]mongo.WriteModel, 0, len(elos))
+	for _, elo := range elos {
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"teamid": elo.TeamID}).
+			SetReplacement(elo).
+			SetUpsert(true))
+	}
+	if _, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("error saving team elos: %w", err)
+	}
+	return nil
+}
+
+// Метод для получения прогресса пересчёта; пустое состояние означает, что пересчёта ещё не было
+func (m *MongoDBEloStore) GetEloState(ctx context.Context) (types.EloState, error) {
+	coll := m.client.Database(m.dbName).Collection(m.stateCollName)
+	var state types.EloState
+	err := coll.FindOne(ctx, bson.M{"_id": eloStateID}).Decode(&state)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return types.EloState{}, nil
+	}
+	if err != nil {
+		return types.EloState{}, fmt.Errorf("error finding elo state: %w", err)
+	}
+	return state, nil
+}
+
+// Метод для сохранения прогресса пересчёта
+func (m *MongoDBEloStore) SaveEloState(ctx context.Context, state types.EloState) error {
+	coll := m.client.Database(m.dbName).Collection(m.stateCollName)
+	update := bson.M{"$set": state}
+	_, err := coll.UpdateOne(ctx, bson.M{"_id": eloStateID}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("error saving elo state: %w", err)
+	}
+	return nil
+}
+
+// Метод для удаления всех рейтингов и прогресса перед полным пересчётом
+func (m *MongoDBEloStore) ResetElo(ctx context.Context) error {
+	db := m.client.Database(m.dbName)
+	if _, err := db.Collection(m.collName).DeleteMany(ctx, bson.M{}); err != nil {
+		return fmt.Errorf("error deleting team elos: %w", err)
+	}
+	if _, err := db.Collection(m.stateCollName).DeleteOne(ctx, bson.M{"_id": eloStateID}); err != nil {
+		return fmt.Errorf("error deleting elo state: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/vsespontanno/tgbot_fschedule/internal/types"
@@ -16,14 +17,30 @@ import (
 type MatchesStore interface {
 	GetMatchesInPeriod(ctx context.Context, league, from, to string) ([]types.Match, error)
 	SaveMatchesToMongoDB(matches []types.Match, from, to string) error
-	UpdateMatchRatingInMongoDB(match types.Match, rating float64) error
+	UpdateMatchRatingInMongoDB(match types.Match, breakdown *types.RatingBreakdown) error
 	UpsertMatch(ctx context.Context, match types.Match) error
+	UpdateMatchResult(ctx context.Context, match types.Match) error
 }
 
 // Интерфейс для взаимодействия с данными матчей в контексте калькуляции рейтинга матчей
 type MatchCalcStore interface {
 	GetRecentMatches(ctx context.Context, teamID int, lastN int) ([]types.Match, error)
 	GetMatchesInPeriod(ctx context.Context, league string, from, to string) ([]types.Match, error)
+	GetFirstLeg(ctx context.Context, match types.Match) (*types.Match, error)
+	GetRecentMatchesForTeams(ctx context.Context, teamIDs []int, lastN int) (map[int][]types.Match, error)
+	GetFinishedMatchesByStage(ctx context.Context, competitionIDs []int, stages []string, teamIDs []int) ([]types.Match, error)
+}
+
+// Интерфейс для получения сыгранных матчей, например для пересчёта рейтинга Эло
+type FinishedMatchesStore interface {
+	GetFinishedMatches(ctx context.Context, since string) ([]types.Match, error)
+}
+
+// Интерфейс для статистики: сыгранные матчи для оценки сил и зрелищности команд и ближайшие матчи для прогноза
+type StatsMatchesStore interface {
+	FinishedMatchesStore
+	GetMatchesInPeriod(ctx context.Context, league string, from, to string) ([]types.Match, error)
+	GetRecentMatches(ctx context.Context, teamID int, lastN int) ([]types.Match, error)
 }
 
 // Структура для взаимодействия с данными матчей и команд
@@ -68,16 +85,18 @@ func (m *MongoDBMatchesStore) GetMatches(ctx context.Context) ([]types.Match, er
 	return matches, nil
 }
 
-// Метод для получения последнтх матчей той или иной команды из MONGODB
+// Метод для получения последних завершённых матчей той или иной команды из MONGODB
+// Матчи отсортированы по времени начала, самый свежий - первый
 func (m *MongoDBMatchesStore) GetRecentMatches(ctx context.Context, teamID int, lastN int) ([]types.Match, error) {
 	collection := m.client.Database(m.dbName).Collection(m.collName)
 	filter := bson.M{
+		"status": "FINISHED",
 		"$or": []bson.M{
-			{"homeTeam.id": teamID},
-			{"awayTeam.id": teamID},
+			{"hometeam.id": teamID},
+			{"awayteam.id": teamID},
 		},
 	}
-	opts := options.Find().SetSort(bson.M{"date": -1}).SetLimit(int64(lastN))
+	opts := options.Find().SetSort(bson.M{"utcdate": -1}).SetLimit(int64(lastN))
 	cursor, err := collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find recent matches: %w", err)
@@ -113,12 +132,17 @@ func (m *MongoDBMatchesStore) SaveMatchesToMongoDB(matches []types.Match, from,
 	return nil
 }
 
-// Метод для обновления рейтинга матча в базе MongoDB
-func (m *MongoDBMatchesStore) UpdateMatchRatingInMongoDB(match types.Match, rating float64) error {
+// Метод для обновления рейтинга матча и его разбора в базе MongoDB
+func (m *MongoDBMatchesStore) UpdateMatchRatingInMongoDB(match types.Match, breakdown *types.RatingBreakdown) error {
 	collection := m.client.Database(m.dbName).Collection(m.collName)
 
 	filter := bson.M{"id": match.ID}
-	update := bson.M{"$set": bson.M{"rating": rating}}
+	update := bson.M{"$set": bson.M{
+		"rating":          breakdown.Rating,
+		"ratingstrategy":  breakdown.Strategy,
+		"ratingversion":   breakdown.StrategyVersion,
+		"ratingbreakdown": breakdown,
+	}}
 
 	_, err := collection.UpdateOne(context.TODO(), filter, update)
 	if err != nil {
@@ -138,6 +162,28 @@ func (m *MongoDBMatchesStore) UpsertMatch(ctx context.Context, match types.Match
 	return err
 }
 
+// Метод для обновления статуса и счёта матча без изменения его рейтинга
+// Если матча ещё нет в базе, он сохраняется целиком
+func (m *MongoDBMatchesStore) UpdateMatchResult(ctx context.Context, match types.Match) error {
+	collection := m.client.Database(m.dbName).Collection(m.collName)
+	update := bson.M{"$set": bson.M{
+		"status":  match.Status,
+		"score":   match.Score,
+		"utcdate": match.UTCDate,
+	}}
+	res, err := collection.UpdateOne(ctx, bson.M{"id": match.ID}, update)
+	if err != nil {
+		return fmt.Errorf("error updating result of match %d: %w", match.ID, err)
+	}
+	if res.MatchedCount > 0 {
+		return nil
+	}
+	if _, err := collection.InsertOne(ctx, match); err != nil {
+		return fmt.Errorf("error inserting match %d: %w", match.ID, err)
+	}
+	return nil
+}
+
 // Метод для получения всех матчей за тот или иной период
 func (m *MongoDBMatchesStore) GetMatchesInPeriod(ctx context.Context, league, from, to string) ([]types.Match, error) {
 	coll := m.client.Database(m.dbName).Collection(m.collName)
@@ -175,3 +221,113 @@ func (m *MongoDBMatchesStore) GetMatchesInPeriod(ctx context.Context, league, fr
 	}
 	return matches, nil
 }
+
+// Метод для получения завершённых матчей, начиная с даты since (UTCDate, включительно)
+// Пустой since означает всю историю; матчи отсортированы по дате
+func (m *MongoDBMatchesStore) GetFinishedMatches(ctx context.Context, since string) ([]types.Match, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	filter := bson.M{"status": "FINISHED"}
+	if since != "" {
+		filter["utcdate"] = bson.M{"$gte": since}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "utcdate", Value: 1}, {Key: "id", Value: 1}})
+
+	cur, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding finished matches since %q: %w", since, err)
+	}
+	defer cur.Close(ctx)
+
+	var matches []types.Match
+	if err := cur.All(ctx, &matches); err != nil {
+		return nil, fmt.Errorf("error decoding finished matches: %w", err)
+	}
+	return matches, nil
+}
+
+// Метод для поиска первого матча кубкового противостояния
+// Первый матч - завершённая игра той же стадии того же турнира, где хозяева и гости поменяны местами
+// Возвращает nil, если такого матча нет
+func (m *MongoDBMatchesStore) GetFirstLeg(ctx context.Context, match types.Match) (*types.Match, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	filter := bson.M{
+		"competition.id": match.Competition.ID,
+		"stage":          match.Stage,
+		"hometeam.id":    match.AwayTeam.ID,
+		"awayteam.id":    match.HomeTeam.ID,
+		"status":         "FINISHED",
+		"utcdate":        bson.M{"$lt": match.UTCDate},
+	}
+	opts := options.FindOne().SetSort(bson.M{"utcdate": -1})
+
+	var firstLeg types.Match
+	err := coll.FindOne(ctx, filter, opts).Decode(&firstLeg)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding first leg for match %d: %w", match.ID, err)
+	}
+	return &firstLeg, nil
+}
+
+// Метод для получения последних завершённых матчей сразу для нескольких команд одним запросом
+// Возвращает не больше lastN матчей на команду, самый свежий - первый
+func (m *MongoDBMatchesStore) GetRecentMatchesForTeams(ctx context.Context, teamIDs []int, lastN int) (map[int][]types.Match, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	ids := bson.M{"$in": teamIDs}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"status": "FINISHED",
+			"$or":    []bson.M{{"hometeam.id": ids}, {"awayteam.id": ids}},
+		}}},
+		{{Key: "$sort", Value: bson.M{"utcdate": -1}}},
+		{{Key: "$project", Value: bson.M{"match": "$$ROOT", "team": []string{"$hometeam.id", "$awayteam.id"}}}},
+		{{Key: "$unwind", Value: "$team"}},
+		{{Key: "$match", Value: bson.M{"team": ids}}},
+		{{Key: "$group", Value: bson.M{"_id": "$team", "matches": bson.M{"$push": "$match"}}}},
+		{{Key: "$project", Value: bson.M{"matches": bson.M{"$slice": []interface{}{"$matches", lastN}}}}},
+	}
+
+	cur, err := coll.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating recent matches: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var groups []struct {
+		TeamID  int           `bson:"_id"`
+		Matches []types.Match `bson:"matches"`
+	}
+	if err := cur.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("error decoding recent matches: %w", err)
+	}
+	result := make(map[int][]types.Match, len(groups))
+	for _, g := range groups {
+		result[g.TeamID] = g.Matches
+	}
+	return result, nil
+}
+
+// Метод для получения завершённых матчей указанных турниров и стадий с участием команд
+// Используется для поиска первых матчей кубковых противостояний сразу для списка матчей
+func (m *MongoDBMatchesStore) GetFinishedMatchesByStage(ctx context.Context, competitionIDs []int, stages []string, teamIDs []int) ([]types.Match, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	filter := bson.M{
+		"status":         "FINISHED",
+		"competition.id": bson.M{"$in": competitionIDs},
+		"stage":          bson.M{"$in": stages},
+		"hometeam.id":    bson.M{"$in": teamIDs},
+	}
+	cur, err := coll.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error finding matches by stage: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var matches []types.Match
+	if err := cur.All(ctx, &matches); err != nil {
+		return nil, fmt.Errorf("error decoding matches by stage: %w", err)
+	}
+	return matches, nil
+}
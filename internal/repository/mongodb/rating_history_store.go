@@ -0,0 +1,97 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Интерфейс для взаимодействия с историей рейтингов матчей
+type RatingHistoryStore interface {
+	AddRatingPoints(ctx context.Context, points []types.RatingPoint) error
+	GetRatingHistory(ctx context.Context, matchID int) ([]types.RatingPoint, error)
+	GetLatestRatingPoints(ctx context.Context, matchIDs []int) (map[int]types.RatingPoint, error)
+}
+
+// Структура для хранения истории рейтингов: по документу на каждый пересчёт матча
+type MongoDBRatingHistoryStore struct {
+	dbName   string
+	client   *mongo.Client
+	collName string
+}
+
+// Конструктор структуры для взаимодействия с историей рейтингов
+func NewMongoDBRatingHistoryStore(client *mongo.Client, dbName string) *MongoDBRatingHistoryStore {
+	return &MongoDBRatingHistoryStore{
+		client:   client,
+		dbName:   dbName,
+		collName: "rating_history",
+	}
+}
+
+// Метод для добавления точек истории
+func (m *MongoDBRatingHistoryStore) AddRatingPoints(ctx context.Context, points []types.RatingPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	documents := make([]interface{}, len(points))
+	for i, point := range points {
+		documents[i] = point
+	}
+	if _, err := coll.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("error inserting rating history: %w", err)
+	}
+	return nil
+}
+
+// Метод для получения истории рейтинга матча, старые точки первыми
+func (m *MongoDBRatingHistoryStore) GetRatingHistory(ctx context.Context, matchID int) ([]types.RatingPoint, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	opts := options.Find().SetSort(bson.D{{Key: "computedat", Value: 1}})
+	cursor, err := coll.Find(ctx, bson.M{"matchid": matchID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding rating history: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var points []types.RatingPoint
+	if err := cursor.All(ctx, &points); err != nil {
+		return nil, fmt.Errorf("error decoding rating history: %w", err)
+	}
+	return points, nil
+}
+
+// Метод для получения последней точки истории каждого матча из списка
+// Матчи без истории в результат не попадают
+func (m *MongoDBRatingHistoryStore) GetLatestRatingPoints(ctx context.Context, matchIDs []int) (map[int]types.RatingPoint, error) {
+	latest := make(map[int]types.RatingPoint, len(matchIDs))
+	if len(matchIDs) == 0 {
+		return latest, nil
+	}
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"matchid": bson.M{"$in": matchIDs}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "computedat", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$matchid", "point": bson.M{"$first": "$$ROOT"}}}},
+	}
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating rating history: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var rows []struct {
+		Point types.RatingPoint `bson:"point"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("error decoding rating history: %w", err)
+	}
+	for _, row := range rows {
+		latest[row.Point.MatchID] = row.Point
+	}
+	return latest, nil
+}
@@ -0,0 +1,84 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Интерфейс для взаимодействия с реестром дерби
+type RivalryStore interface {
+	RivalryCalcStore
+	UpsertRivalry(ctx context.Context, rivalry types.Rivalry) error
+}
+
+// Интерфейс для получения дерби в контексте калькуляции рейтинга матчей
+type RivalryCalcStore interface {
+	GetRivalry(ctx context.Context, firstTeamID, secondTeamID int) (*types.Rivalry, error)
+	GetRivalries(ctx context.Context) ([]types.Rivalry, error)
+}
+
+// Структура для хранения дерби в коллекции rivalries
+type MongoDBRivalryStore struct {
+	dbName   string
+	client   *mongo.Client
+	collName string
+}
+
+// Конструктор структуры для взаимодействия с реестром дерби
+func NewMongoDBRivalryStore(client *mongo.Client, dbName string) *MongoDBRivalryStore {
+	return &MongoDBRivalryStore{
+		client:   client,
+		dbName:   dbName,
+		collName: "rivalries",
+	}
+}
+
+// Метод для получения дерби пары команд в любом порядке; возвращает nil, если пара не дерби
+func (m *MongoDBRivalryStore) GetRivalry(ctx context.Context, firstTeamID, secondTeamID int) (*types.Rivalry, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	teamA, teamB := types.RivalryPair(firstTeamID, secondTeamID)
+	var rivalry types.Rivalry
+	err := coll.FindOne(ctx, bson.M{"teama": teamA, "teamb": teamB}).Decode(&rivalry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding rivalry %d - %d: %w", teamA, teamB, err)
+	}
+	return &rivalry, nil
+}
+
+// Метод для получения всех дерби, самые принципиальные первыми
+func (m *MongoDBRivalryStore) GetRivalries(ctx context.Context) ([]types.Rivalry, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	opts := options.Find().SetSort(bson.D{{Key: "intensity", Value: -1}, {Key: "teamaname", Value: 1}})
+	cur, err := coll.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding rivalries: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var rivalries []types.Rivalry
+	if err := cur.All(ctx, &rivalries); err != nil {
+		return nil, fmt.Errorf("error decoding rivalries: %w", err)
+	}
+	return rivalries, nil
+}
+
+// Метод для добавления или обновления дерби
+func (m *MongoDBRivalryStore) UpsertRivalry(ctx context.Context, rivalry types.Rivalry) error {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	filter := bson.M{"teama": rivalry.TeamA, "teamb": rivalry.TeamB}
+	_, err := coll.ReplaceOne(ctx, filter, rivalry, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("error saving rivalry %d - %d: %w", rivalry.TeamA, rivalry.TeamB, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Интерфейс для хранения текущих сезонов лиг
+type SeasonStore interface {
+	SaveSeason(ctx context.Context, season types.Season) error
+	GetSeason(ctx context.Context, code string) (*types.Season, error)
+	GetSeasons(ctx context.Context) ([]types.Season, error)
+}
+
+// Структура для хранения сезонов в коллекции seasons, по документу на лигу
+type MongoDBSeasonStore struct {
+	dbName   string
+	client   *mongo.Client
+	collName string
+}
+
+// Конструктор структуры для взаимодействия с сезонами
+func NewMongoDBSeasonStore(client *mongo.Client, dbName string) *MongoDBSeasonStore {
+	return &MongoDBSeasonStore{
+		client:   client,
+		dbName:   dbName,
+		collName: "seasons",
+	}
+}
+
+// Метод для сохранения сезона лиги; прежний сезон этой лиги заменяется
+func (m *MongoDBSeasonStore) SaveSeason(ctx context.Context, season types.Season) error {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	_, err := coll.ReplaceOne(ctx, bson.M{"code": season.Code}, season, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("error saving season of %s: %w", season.Code, err)
+	}
+	return nil
+}
+
+// Метод для получения сезона лиги по коду; возвращает nil, если сезон ещё не загружен
+func (m *MongoDBSeasonStore) GetSeason(ctx context.Context, code string) (*types.Season, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	var season types.Season
+	err := coll.FindOne(ctx, bson.M{"code": code}).Decode(&season)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding season of %s: %w", code, err)
+	}
+	return &season, nil
+}
+
+// Метод для получения сезонов всех лиг
+func (m *MongoDBSeasonStore) GetSeasons(ctx context.Context) ([]types.Season, error) {
+	coll := m.client.Database(m.dbName).Collection(m.collName)
+	cur, err := coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "code", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("error finding seasons: %w", err)
+	}
+	defer cur.Close(ctx)
+
+	var seasons []types.Season
+	if err := cur.All(ctx, &seasons); err != nil {
+		return nil, fmt.Errorf("error decoding seasons: %w", err)
+	}
+	return seasons, nil
+}
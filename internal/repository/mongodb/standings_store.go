@@ -19,6 +19,7 @@ type StandingsStore interface {
 // Интерфейс для взаимодействия с данными турнирных таблиц в контексте калькуляции рейтинга матчей
 type StandingsCalcStore interface {
 	GetTeamStanding(ctx context.Context, collectionName string, id int) (int, error)
+	GetStandings(ctx context.Context, collectionName string) ([]types.Standing, error)
 }
 
 // Структура для взаимодействия с данными турнирных таблиц
@@ -52,25 +53,25 @@ func (m *MongoDBStandingsStore) GetStandings(ctx context.Context, collectionName
 		return nil, fmt.Errorf("error decoding standings: %w", err)
 	}
 
-	// Добавляем логирование
-	fmt.Printf("Raw standings from MongoDB for %s:\n", collectionName)
-	for i, s := range standings {
-		fmt.Printf("Standing %d: %+v\n", i, s)
-	}
-
 	return standings, nil
 }
 
 // Метод для для сохранения турнирных таблиц в MONGODB
+// Таблица заменяется целиком, чтобы в коллекции не копились устаревшие строки
 func (m *MongoDBStandingsStore) SaveStandings(ctx context.Context, collectionName string, standings []types.Standing) error {
 	collection := m.client.Database(m.dbName).Collection(collectionName + "_standings")
+	if len(standings) == 0 {
+		return nil
+	}
 
-	// Insert new standings
 	documents := make([]interface{}, len(standings))
 	for i, standing := range standings {
 		documents[i] = standing
 	}
 
+	if _, err := collection.DeleteMany(ctx, bson.M{}); err != nil {
+		return fmt.Errorf("error deleting old standings in %s: %w", collectionName, err)
+	}
 	_, err := collection.InsertMany(ctx, documents)
 	return err
 }
@@ -2,7 +2,10 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 
 	"github.com/vsespontanno/tgbot_fschedule/internal/types"
 
@@ -21,6 +24,12 @@ type TeamsStore interface {
 type TeamsCalcStore interface {
 	GetTeamLeague(ctx context.Context, collectionName string, id int) (string, error)
 	GetTeamsShortName(ctx context.Context, collectionName string, fullName string) (string, error)
+	GetTeamsByIDs(ctx context.Context, collectionName string, ids []int) ([]types.Team, error)
+}
+
+// Интерфейс для поиска команды по названию или ID, например в админских командах
+type TeamsSearchStore interface {
+	FindTeam(ctx context.Context, collectionName string, query string) (*types.Team, error)
 }
 
 type MongoDBTeamsStore struct {
@@ -76,3 +85,41 @@ func (m *MongoDBTeamsStore) UpsertTeamToMongoDB(ctx context.Context, collectionN
 	_, err := collection.UpdateOne(ctx, filter, update, opts)
 	return err
 }
+
+// FindTeam ищет команду по ID или по полному, короткому названию или аббревиатуре без учёта регистра
+// Возвращает nil, если команда не найдена
+func (m *MongoDBTeamsStore) FindTeam(ctx context.Context, collectionName string, query string) (*types.Team, error) {
+	collection := m.client.Database(m.dbName).Collection(collectionName)
+	var filter bson.M
+	if id, err := strconv.Atoi(query); err == nil {
+		filter = bson.M{"id": id}
+	} else {
+		pattern := bson.M{"$regex": "^" + regexp.QuoteMeta(query) + "$", "$options": "i"}
+		filter = bson.M{"$or": []bson.M{{"name": pattern}, {"shortname": pattern}, {"tla": pattern}}}
+	}
+	var team types.Team
+	err := collection.FindOne(ctx, filter).Decode(&team)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding team %q: %w", query, err)
+	}
+	return &team, nil
+}
+
+// GetTeamsByIDs возвращает команды с указанными ID одним запросом
+func (m *MongoDBTeamsStore) GetTeamsByIDs(ctx context.Context, collectionName string, ids []int) ([]types.Team, error) {
+	collection := m.client.Database(m.dbName).Collection(collectionName)
+	cursor, err := collection.Find(ctx, bson.M{"id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("error finding teams in %s: %w", collectionName, err)
+	}
+	defer cursor.Close(ctx)
+
+	var teams []types.Team
+	if err := cursor.All(ctx, &teams); err != nil {
+		return nil, fmt.Errorf("error decoding teams in %s: %w", collectionName, err)
+	}
+	return teams, nil
+}
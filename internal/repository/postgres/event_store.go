@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Сколько строк попадает в топы отчёта
+const reportTopN = 10
+
+// Интерфейс для работы с событиями аналитики в PostgreSQL
+type EventStore interface {
+	PersonalDataStore
+	SaveEvents(ctx context.Context, events []types.Event) error
+	GetDailyReport(ctx context.Context, day time.Time) (*types.UsageReport, error)
+	GetLeagueUsage(ctx context.Context, telegramID int64, since time.Time) ([]types.UsageCount, error)
+}
+
+// PGEventStore реализует интерфейс EventStore
+type PGEventStore struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+// NewPGEventStore создает новый экземпляр PGEventStore
+func NewPGEventStore(db *sql.DB) EventStore {
+	return &PGEventStore{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// SaveEvents сохраняет пачку событий одним INSERT
+func (s *PGEventStore) SaveEvents(ctx context.Context, events []types.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	query := s.builder.Insert("events").
+		Columns("telegram_id", "chat_type", "kind", "name", "league", "latency_ms", "outcome", "created_at")
+	for _, e := range events {
+		query = query.Values(e.TelegramID, e.ChatType, e.Kind, e.Name, sql.NullString{String: e.League, Valid: e.League != ""}, e.LatencyMs, e.Outcome, e.CreatedAt)
+	}
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("building insert query: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, sqlStr, args...); err != nil {
+		return fmt.Errorf("executing insert: %w", err)
+	}
+	return nil
+}
+
+// GetDailyReport собирает агрегированный отчёт за сутки (UTC)
+func (s *PGEventStore) GetDailyReport(ctx context.Context, day time.Time) (*types.UsageReport, error) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	period := sq.And{sq.GtOrEq{"created_at": from}, sq.Lt{"created_at": to}}
+	report := &types.UsageReport{Day: from}
+
+	sqlStr, args, err := s.builder.
+		Select("COUNT(*)", "COUNT(DISTINCT telegram_id)", "COUNT(*) FILTER (WHERE outcome = 'error')").
+		From("events").
+		Where(period).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building totals query: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, sqlStr, args...).Scan(&report.Events, &report.Users, &report.Errors); err != nil {
+		return nil, fmt.Errorf("querying totals: %w", err)
+	}
+
+	report.TopCommands, err = s.topCounts(ctx, "name", period)
+	if err != nil {
+		return nil, err
+	}
+	report.TopLeagues, err = s.topCounts(ctx, "league", sq.And{period, sq.NotEq{"league": nil}})
+	if err != nil {
+		return nil, err
+	}
+	report.Latency, err = s.latencyStats(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetLeagueUsage считает, сколько раз пользователь открывал каждую лигу начиная с since
+func (s *PGEventStore) GetLeagueUsage(ctx context.Context, telegramID int64, since time.Time) ([]types.UsageCount, error) {
+	sqlStr, args, err := s.builder.
+		Select("league", "COUNT(*) AS cnt").
+		From("events").
+		Where(sq.And{sq.Eq{"telegram_id": telegramID}, sq.GtOrEq{"created_at": since}, sq.NotEq{"league": nil}}).
+		GroupBy("league").
+		OrderBy("cnt DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building league usage query: %w", err)
+	}
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying league usage: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []types.UsageCount
+	for rows.Next() {
+		var c types.UsageCount
+		if err := rows.Scan(&c.Name, &c.Count); err != nil {
+			return nil, fmt.Errorf("scanning league usage: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// Считает самые частые значения колонки за период
+func (s *PGEventStore) topCounts(ctx context.Context, column string, where sq.Sqlizer) ([]types.UsageCount, error) {
+	sqlStr, args, err := s.builder.
+		Select(column, "COUNT(*) AS cnt").
+		From("events").
+		Where(where).
+		GroupBy(column).
+		OrderBy("cnt DESC").
+		Limit(reportTopN).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building top %s query: %w", column, err)
+	}
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying top %s: %w", column, err)
+	}
+	defer rows.Close()
+
+	var counts []types.UsageCount
+	for rows.Next() {
+		var c types.UsageCount
+		if err := rows.Scan(&c.Name, &c.Count); err != nil {
+			return nil, fmt.Errorf("scanning top %s: %w", column, err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// Считает p95 времени ответа по каждому действию за период
+func (s *PGEventStore) latencyStats(ctx context.Context, where sq.Sqlizer) ([]types.LatencyStat, error) {
+	sqlStr, args, err := s.builder.
+		Select("name", "COUNT(*)", "percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms) AS p95").
+		From("events").
+		Where(where).
+		GroupBy("name").
+		OrderBy("p95 DESC").
+		Limit(reportTopN).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building latency query: %w", err)
+	}
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying latency: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []types.LatencyStat
+	for rows.Next() {
+		var l types.LatencyStat
+		if err := rows.Scan(&l.Name, &l.Count, &l.P95Ms); err != nil {
+			return nil, fmt.Errorf("scanning latency: %w", err)
+		}
+		stats = append(stats, l)
+	}
+	return stats, rows.Err()
+}
+
+// Section возвращает название раздела с событиями в выгрузке
+func (s *PGEventStore) Section() string {
+	return "events"
+}
+
+// ExportUserData возвращает все события пользователя
+func (s *PGEventStore) ExportUserData(ctx context.Context, telegramID int64) (interface{}, error) {
+	sqlStr, args, err := s.builder.
+		Select("telegram_id", "COALESCE(chat_type, '')", "kind", "name", "COALESCE(league, '')", "latency_ms", "outcome", "created_at").
+		From("events").
+		Where(sq.Eq{"telegram_id": telegramID}).
+		OrderBy("created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building select query: %w", err)
+	}
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []types.Event{}
+	for rows.Next() {
+		var e types.Event
+		if err := rows.Scan(&e.TelegramID, &e.ChatType, &e.Kind, &e.Name, &e.League, &e.LatencyMs, &e.Outcome, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning event: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// DeleteUserData удаляет все события пользователя
+func (s *PGEventStore) DeleteUserData(ctx context.Context, telegramID int64) error {
+	sqlStr, args, err := s.builder.Delete("events").
+		Where(sq.Eq{"telegram_id": telegramID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building delete query: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, sqlStr, args...); err != nil {
+		return fmt.Errorf("executing delete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Интерфейс для работы с предпочтениями пользователей в PostgreSQL
+type PreferenceStore interface {
+	PersonalDataStore
+	GetPreferences(ctx context.Context, telegramID int64) ([]types.Preference, error)
+	SavePreference(ctx context.Context, pref types.Preference) error
+	DeletePreference(ctx context.Context, telegramID int64, kind, value string) (bool, error)
+	GetFollowers(ctx context.Context, kind, value string) ([]int64, error)
+}
+
+// PGPreferenceStore реализует интерфейс PreferenceStore
+type PGPreferenceStore struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+// NewPGPreferenceStore создает новый экземпляр PGPreferenceStore
+func NewPGPreferenceStore(db *sql.DB) PreferenceStore {
+	return &PGPreferenceStore{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// GetPreferences возвращает все предпочтения пользователя в порядке добавления
+func (s *PGPreferenceStore) GetPreferences(ctx context.Context, telegramID int64) ([]types.Preference, error) {
+	sqlStr, args, err := s.builder.
+		Select("telegram_id", "kind", "value", "label", "mode", "created_at").
+		From("user_preferences").
+		Where(sq.Eq{"telegram_id": telegramID}).
+		OrderBy("created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building select query: %w", err)
+	}
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying preferences: %w", err)
+	}
+	defer rows.Close()
+
+	prefs := []types.Preference{}
+	for rows.Next() {
+		var p types.Preference
+		if err := rows.Scan(&p.TelegramID, &p.Kind, &p.Value, &p.Label, &p.Mode, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning preference: %w", err)
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs, rows.Err()
+}
+
+// SavePreference добавляет предпочтение
+// Если команда или лига уже есть у пользователя, меняет режим (подписка <-> скрытие)
+func (s *PGPreferenceStore) SavePreference(ctx context.Context, pref types.Preference) error {
+	sqlStr, args, err := s.builder.Insert("user_preferences").
+		Columns("telegram_id", "kind", "value", "label", "mode").
+		Values(pref.TelegramID, pref.Kind, pref.Value, pref.Label, pref.Mode).
+		Suffix("ON CONFLICT (telegram_id, kind, value) DO UPDATE SET mode = EXCLUDED.mode, label = EXCLUDED.label").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building insert query: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, sqlStr, args...); err != nil {
+		return fmt.Errorf("executing insert: %w", err)
+	}
+	return nil
+}
+
+// DeletePreference удаляет предпочтение; возвращает false, если его не было
+func (s *PGPreferenceStore) DeletePreference(ctx context.Context, telegramID int64, kind, value string) (bool, error) {
+	sqlStr, args, err := s.builder.Delete("user_preferences").
+		Where(sq.Eq{"telegram_id": telegramID, "kind": kind, "value": value}).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("building delete query: %w", err)
+	}
+	res, err := s.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return false, fmt.Errorf("executing delete: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("getting rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetFollowers возвращает Telegram ID пользователей, подписанных на команду или лигу
+func (s *PGPreferenceStore) GetFollowers(ctx context.Context, kind, value string) ([]int64, error) {
+	sqlStr, args, err := s.builder.
+		Select("telegram_id").
+		From("user_preferences").
+		Where(sq.Eq{"kind": kind, "value": value, "mode": types.PreferenceFollow}).
+		OrderBy("telegram_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building select query: %w", err)
+	}
+	rows, err := s.db.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying followers: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning follower: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Section возвращает название раздела с предпочтениями в выгрузке
+func (s *PGPreferenceStore) Section() string {
+	return "preferences"
+}
+
+// ExportUserData возвращает все предпочтения пользователя
+func (s *PGPreferenceStore) ExportUserData(ctx context.Context, telegramID int64) (interface{}, error) {
+	return s.GetPreferences(ctx, telegramID)
+}
+
+// DeleteUserData удаляет все предпочтения пользователя
+func (s *PGPreferenceStore) DeleteUserData(ctx context.Context, telegramID int64) error {
+	sqlStr, args, err := s.builder.Delete("user_preferences").
+		Where(sq.Eq{"telegram_id": telegramID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building delete query: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, sqlStr, args...); err != nil {
+		return fmt.Errorf("executing delete: %w", err)
+	}
+	return nil
+}
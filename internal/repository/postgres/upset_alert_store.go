@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Интерфейс для учёта разосланных уведомлений о сенсациях
+type UpsetAlertStore interface {
+	MarkUpsetAlerted(ctx context.Context, matchID int, surprise float64) (bool, error)
+}
+
+// PGUpsetAlertStore реализует интерфейс UpsetAlertStore
+type PGUpsetAlertStore struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+// NewPGUpsetAlertStore создает новый экземпляр PGUpsetAlertStore
+func NewPGUpsetAlertStore(db *sql.DB) UpsetAlertStore {
+	return &PGUpsetAlertStore{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// MarkUpsetAlerted отмечает, что о сенсации в матче уже сообщили
+// Возвращает false, если матч был отмечен раньше: уведомление рассылать не нужно
+func (s *PGUpsetAlertStore) MarkUpsetAlerted(ctx context.Context, matchID int, surprise float64) (bool, error) {
+	sqlStr, args, err := s.builder.Insert("upset_alerts").
+		Columns("match_id", "surprise").
+		Values(matchID, surprise).
+		Suffix("ON CONFLICT (match_id) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("building insert query: %w", err)
+	}
+	res, err := s.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return false, fmt.Errorf("executing insert: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("getting rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
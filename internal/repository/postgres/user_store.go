@@ -12,8 +12,19 @@ import (
 	sq "github.com/Masterminds/squirrel"
 )
 
+// Интерфейс для хранилищ, в которых лежат персональные данные пользователя.
+// Каждая новая таблица с данными пользователя должна его реализовать,
+// тогда она автоматически попадёт в выгрузку /export_me и в удаление /delete_me
+type PersonalDataStore interface {
+	// Название раздела в JSON-выгрузке
+	Section() string
+	ExportUserData(ctx context.Context, telegramID int64) (interface{}, error)
+	DeleteUserData(ctx context.Context, telegramID int64) error
+}
+
 // Интерфейс для работы с пользователями в PostgreSQL
 type UserStore interface {
+	PersonalDataStore
 	GetUserByTelegramID(ctx context.Context, telegramID int64) (*types.User, error)
 	SaveUser(ctx context.Context, user *types.User) error
 }
@@ -46,13 +57,17 @@ func (s *PGUserStore) GetUserByTelegramID(ctx context.Context, telegramID int64)
 
 	row := s.db.QueryRowContext(ctx, sqlStr, args...)
 
-	var user types.User
-	if err := row.Scan(&user.ID, &user.TelegramID, &user.Username); err != nil {
+	var (
+		user     types.User
+		username sql.NullString
+	)
+	if err := row.Scan(&user.ID, &user.TelegramID, &username, &user.CreatedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
+	user.Username = username.String
 
 	return &user, nil
 }
@@ -96,3 +111,37 @@ func (s *PGUserStore) SaveUser(ctx context.Context, user *types.User) error {
 	log.Printf("Inserted user %d (rows affected: %d)", user.TelegramID, rowsAffected)
 	return nil
 }
+
+// Section возвращает название раздела с данными пользователя в выгрузке
+func (s *PGUserStore) Section() string {
+	return "user"
+}
+
+// ExportUserData возвращает всё, что хранится о пользователе в таблице users
+// Если пользователя нет, возвращает nil без ошибки
+func (s *PGUserStore) ExportUserData(ctx context.Context, telegramID int64) (interface{}, error) {
+	user, err := s.GetUserByTelegramID(ctx, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user %d: %w", telegramID, err)
+	}
+	return user, nil
+}
+
+// DeleteUserData удаляет строку пользователя из таблицы users
+func (s *PGUserStore) DeleteUserData(ctx context.Context, telegramID int64) error {
+	sqlStr, args, err := s.builder.Delete("users").
+		Where(sq.Eq{"telegram_id": telegramID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building delete query: %w", err)
+	}
+
+	res, err := s.db.ExecContext(ctx, sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("executing delete: %w", err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	log.Printf("Deleted user %d (rows affected: %d)", telegramID, rowsAffected)
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/db"
+	mongorepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	"github.com/joho/godotenv"
+)
+
+// Офлайн-проверка рейтинга: прогоняет сыгранные матчи и сравнивает рейтинг с тем, насколько матч оказался зрелищным
+// Матчи берутся из коллекции matches или из JSON-файла в формате ответа football-data ({"matches": [...]})
+//
+//	go run ./internal/scripts/backtest -profiles configs/rating_profile.json,new_profile.json -since 2024-08-01
+func main() {
+	fixture := flag.String("fixture", "", "JSON file with matches instead of MongoDB")
+	profiles := flag.String("profiles", "", "comma-separated rating profile files (empty - built-in default profile)")
+	strategies := flag.String("strategies", "heuristic,balance,elo", "comma-separated rating strategies")
+	since := flag.String("since", "", "score only matches from this date; earlier matches only build history")
+	flag.Parse()
+
+	ctx := context.Background()
+	matches, rivalries, err := loadMatches(ctx, *fixture)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var candidates []service.BacktestCandidate
+	for _, path := range splitList(*profiles, "") {
+		ratingProfiles, err := service.NewRatingProfiles(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		registry := service.NewRatingStrategies(
+			service.NewHeuristicStrategy(ratingProfiles),
+			service.NewBalanceStrategy(ratingProfiles),
+			service.NewEloStrategy(ratingProfiles),
+		)
+		profile := ratingProfiles.Current()
+		for _, name := range splitList(*strategies, "heuristic") {
+			strategy, err := registry.Get(name)
+			if err != nil {
+				log.Fatal(err)
+			}
+			candidates = append(candidates, service.BacktestCandidate{
+				Name:     fmt.Sprintf("%s v%d / %s", profile.Name, profile.Version, strategy.Name()),
+				Strategy: strategy,
+			})
+		}
+	}
+
+	log.Printf("Replaying %d matches with %d candidates...", len(matches), len(candidates))
+	rows := service.Backtest(ctx, matches, rivalries, candidates, *since)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "candidate\tleague\tmatches\tgoals\tlate\tupset\texcitement\t")
+	for _, row := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t\n", row.Candidate, row.League, row.Matches,
+			formatCorrelation(row.Goals), formatCorrelation(row.Late), formatCorrelation(row.Upset), formatCorrelation(row.Excitement))
+	}
+	w.Flush()
+}
+
+// Загружает матчи из файла или из MongoDB; дерби берутся только из MongoDB
+func loadMatches(ctx context.Context, fixture string) ([]types.Match, []types.Rivalry, error) {
+	if fixture != "" {
+		data, err := os.ReadFile(fixture)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading fixture: %w", err)
+		}
+		var response types.MatchesResponse
+		if err := json.Unmarshal(data, &response); err != nil {
+			return nil, nil, fmt.Errorf("error decoding fixture: %w", err)
+		}
+		return response.Matches, nil, nil
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file, using environment")
+	}
+	mongoClient, err := db.ConnectToMongoDB(os.Getenv("MONGODB_URI"))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer mongoClient.Disconnect(context.TODO())
+
+	matches, err := mongorepo.NewMongoDBMatchesStore(mongoClient, "football", "matches").GetFinishedMatches(ctx, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	rivalries, err := mongorepo.NewMongoDBRivalryStore(mongoClient, "football").GetRivalries(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return matches, rivalries, nil
+}
+
+// Разбивает список через запятую; пустой список превращается в список из одного значения по умолчанию
+func splitList(list, fallback string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return []string{fallback}
+	}
+	return items
+}
+
+func formatCorrelation(value float64) string {
+	if math.IsNaN(value) {
+		return "-"
+	}
+	return fmt.Sprintf("%+.3f", value)
+}
@@ -2,13 +2,14 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"time"
 
 	apiClient "github.com/vsespontanno/tgbot_fschedule/internal/client"
+	"github.com/vsespontanno/tgbot_fschedule/internal/config"
 	"github.com/vsespontanno/tgbot_fschedule/internal/db"
 	mongorepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
 	"github.com/vsespontanno/tgbot_fschedule/internal/service"
@@ -19,6 +20,11 @@ import (
 )
 
 func main() {
+	strategyName := flag.String("strategy", os.Getenv("RATING_STRATEGY"), "rating strategy name (heuristic, balance, elo)")
+	seasons := flag.Int("seasons", 2, "how many seasons of history to load, counting the active one")
+	upcomingDays := flag.Int("days", 7, "how many days of upcoming matches to load")
+	flag.Parse()
+
 	// Загрузка .env файла
 	err := godotenv.Load()
 	if err != nil {
@@ -26,15 +32,11 @@ func main() {
 	}
 
 	// Получение значений из .env
-	apiKey := os.Getenv("FOOTBALL_DATA_API_KEY")
-	if apiKey == "" {
-		log.Fatal("FOOTBALL_DATA_API_KEY is not set in the .env file")
+	footallClient, err := apiClient.NewFootballData(os.Getenv("FOOTBALL_DATA_API_KEY"), os.Getenv("FOOTBALL_DATA_REPLAY_DIR"), os.Getenv("FOOTBALL_DATA_RECORD_DIR"), apiClient.NewRateLimiter(config.LoadConfig().UpdaterRequestsPerMinute))
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	from := "2025-05-07"
-	to := "2025-05-14"
-	logrus.Infof("Fetching matches from %s to %s…", from, to)
-
 	mongoURI := os.Getenv("MONGODB_URI")
 
 	// Подключение к MongoDB
@@ -44,22 +46,53 @@ func main() {
 	}
 
 	ctx := context.Background()
+	// Границы истории и расписания берутся из текущих сезонов лиг
+	seasonService := service.NewSeasonService(mongorepo.NewMongoDBSeasonStore(mongoClient, "football"), footallClient)
+	if _, err := seasonService.HandleRefreshSeasons(ctx); err != nil {
+		logrus.Warnf("Failed to refresh some seasons, falling back to the calendar: %v", err)
+	}
+	historyStart, err := seasonService.HandleGetHistoryStart(ctx, *seasons)
+	if err != nil {
+		log.Fatal(err)
+	}
+	today := time.Now().UTC()
+	historyEnd := today.AddDate(0, 0, -1).Format("2006-01-02")
+	from := today.Format("2006-01-02")
+	to := today.AddDate(0, 0, *upcomingDays).Format("2006-01-02")
+
 	matchesStore := mongorepo.NewMongoDBMatchesStore(mongoClient, "football", "matches")
 	standingsStore := mongorepo.NewMongoDBStandingsStore(mongoClient, "football")
 	teamsStore := mongorepo.NewMongoDBTeamsStore(mongoClient, "football")
-	footallClient := apiClient.NewFootballAPIClient(http.DefaultClient, apiKey)
-	matchesService := service.NewMatchesService(matchesStore, footallClient)
+	eloStore := mongorepo.NewMongoDBEloStore(mongoClient, "football")
+	rivalryStore := mongorepo.NewMongoDBRivalryStore(mongoClient, "football")
+	historyStore := mongorepo.NewMongoDBRatingHistoryStore(mongoClient, "football")
+	ratingProfiles, err := service.NewRatingProfiles(os.Getenv("RATING_PROFILE_PATH"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	strategies := service.NewRatingStrategies(
+		service.NewHeuristicStrategy(ratingProfiles),
+		service.NewBalanceStrategy(ratingProfiles),
+		service.NewEloStrategy(ratingProfiles),
+	)
+	strategy, err := strategies.Get(*strategyName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	matchesService := service.NewMatchesService(matchesStore, historyStore, footallClient, strategy)
 
-	calculator := service.NewCalculatorAdapter(teamsStore, standingsStore, matchesStore)
-	// Получаем исторические матчи (с 2025-01-01 по 2025-05-06)
-	logrus.Info("Fetching historical matches...")
-	historicalMatches, err := getHistoricalMatches(matchesService)
+	statsService := service.NewStatsService(matchesStore, teamsStore)
+	calculator := service.NewCalculatorAdapter(teamsStore, standingsStore, matchesStore, eloStore, rivalryStore, statsService)
+	// Получаем исторические матчи с начала истории по вчерашний день
+	logrus.Infof("Fetching historical matches from %s to %s...", historyStart, historyEnd)
+	historicalMatches, err := getHistoricalMatches(matchesService, historyStart, historyEnd)
 	if err != nil {
 		logrus.Warnf("Warning: Error fetching historical matches: %v", err)
 	} else {
 		logrus.Infof("Successfully fetched %d historical matches", len(historicalMatches))
 	}
 
+	logrus.Infof("Fetching matches from %s to %s…", from, to)
 	matches, err := footallClient.FetchMatches(ctx, from, to)
 	if err != nil {
 		log.Fatal(err)
@@ -69,32 +102,29 @@ func main() {
 		log.Fatal(err)
 	}
 	fmt.Printf("Successfully saved %d matches\n", len(matches))
-	for _, match := range matches {
-		rating, err := matchesService.CalculateRatingOfMatch(ctx, match, calculator)
-		if err != nil {
-			logrus.Warnf("Error calculating rating for match %v vs %v; error: %v; skipping", match.HomeTeam.Name, match.AwayTeam.Name, err)
-			continue
-
-		}
-		match.Rating = rating
-		err = matchesService.HandleSaveMatchRating(ctx, match, rating)
-		if err != nil {
-			logrus.Errorf("Error updating match rating for match %v; error: %v", match, err)
+	results, err := matchesService.CalculateRatingsOfMatches(ctx, service.UpcomingMatches(matches), calculator)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, result := range results {
+		match := result.Match
+		if result.Err != nil {
+			logrus.Warnf("Error calculating rating for match %v vs %v; error: %v; skipping", match.HomeTeam.Name, match.AwayTeam.Name, result.Err)
 		}
 	}
+	if _, err := matchesService.HandleSaveRatings(ctx, results, service.RatingReasonSchedule); err != nil {
+		logrus.Errorf("Error saving match ratings: %v", err)
+	}
 
 	mongoClient.Disconnect(context.TODO())
 }
 
-// Функция для получения исторических матчей с 2025-01-01 по 2025-05-06
-func getHistoricalMatches(matchesService *service.MatchesService) ([]types.Match, error) {
-	startDate := "2023-06-06"
-	endDate := "2025-07-02"
-
+// Функция для получения исторических матчей с startDate по endDate включительно
+func getHistoricalMatches(matchesService *service.MatchesService, startDate, endDate string) ([]types.Match, error) {
 	var allMatches []types.Match
 	ctx := context.Background()
 
-	// Разбиваем период на 10-дневные интервалы
+	// Разбиваем период на 10-дневные интервалы; лимит запросов соблюдает клиент API
 	currentDate := startDate
 	for currentDate <= endDate {
 		// Вычисляем дату окончания для текущего интервала (максимум 10 дней)
@@ -113,12 +143,6 @@ func getHistoricalMatches(matchesService *service.MatchesService) ([]types.Match
 		allMatches = append(allMatches, matches...)
 		logrus.Infof("Fetched %d matches for period %s to %s", len(matches), currentDate, intervalEndDate)
 
-		// Если это не последний интервал, ждем 10 секунд
-		if intervalEndDate < endDate {
-			logrus.Infof("Waiting 10 seconds before next request...")
-			time.Sleep(10 * time.Second)
-		}
-
 		// Переходим к следующему интервалу
 		currentDate = addDays(intervalEndDate, 1)
 	}
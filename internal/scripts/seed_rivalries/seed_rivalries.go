@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/db"
+	mongoRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	"github.com/joho/godotenv"
+)
+
+// Команда дерби: ID football-data и название для показа
+type derbyTeam struct {
+	id   int
+	name string
+}
+
+// Дерби, которые раньше хранились в профиле рейтинга по коротким названиям команд
+// Команды заданы ID football-data, поэтому пары сохраняются, даже если команды сейчас
+// нет в коллекциях команд (например, она выбыла из лиги), и применятся, когда она вернётся
+// Мюнхенского дерби нет: 1860 играет в третьей лиге, которой нет в football-data
+var legacyDerbys = []struct {
+	first, second derbyTeam
+	intensity     float64
+	notes         string
+}{
+	// Англия (PremierLeague)
+	{derbyTeam{66, "Manchester United"}, derbyTeam{65, "Manchester City"}, 0.27, "Манчестерское дерби"},
+	{derbyTeam{64, "Liverpool"}, derbyTeam{62, "Everton"}, 0.16, "Мерсисайдское дерби"},
+	{derbyTeam{57, "Arsenal"}, derbyTeam{73, "Tottenham"}, 0.25, "Северолондонское дерби"},
+	{derbyTeam{61, "Chelsea"}, derbyTeam{57, "Arsenal"}, 0.25, ""},
+	{derbyTeam{61, "Chelsea"}, derbyTeam{73, "Tottenham"}, 0.25, ""},
+	{derbyTeam{66, "Manchester United"}, derbyTeam{64, "Liverpool"}, 0.26, ""},
+	{derbyTeam{66, "Manchester United"}, derbyTeam{341, "Leeds United"}, 0.15, ""},
+	{derbyTeam{67, "Newcastle"}, derbyTeam{71, "Sunderland"}, 0.14, "Тайн-Уир"},
+
+	// Испания (LaLiga)
+	{derbyTeam{86, "Real Madrid"}, derbyTeam{81, "Barcelona"}, 0.35, "Эль Класико"},
+	{derbyTeam{78, "Atletico Madrid"}, derbyTeam{86, "Real Madrid"}, 0.26, "Мадридское дерби"},
+	{derbyTeam{559, "Sevilla"}, derbyTeam{90, "Real Betis"}, 0.2, "Севильское дерби"},
+	{derbyTeam{81, "Barcelona"}, derbyTeam{80, "Espanyol"}, 0.18, "Барселонское дерби"},
+	{derbyTeam{95, "Valencia"}, derbyTeam{88, "Levante"}, 0.14, "Валенсийское дерби"},
+
+	// Германия (Bundesliga)
+	{derbyTeam{4, "Borussia Dortmund"}, derbyTeam{5, "Bayern"}, 0.28, "Дер Классикер"},
+	{derbyTeam{6, "Schalke 04"}, derbyTeam{4, "Borussia Dortmund"}, 0.16, "Рурское дерби"},
+	{derbyTeam{7, "Hamburger SV"}, derbyTeam{12, "Werder Bremen"}, 0.15, "Северное дерби"},
+	{derbyTeam{1, "Cologne"}, derbyTeam{18, "Borussia Gladbach"}, 0.14, ""},
+
+	// Италия (SerieA)
+	{derbyTeam{108, "Inter"}, derbyTeam{98, "Milan"}, 0.29, "Миланское дерби"},
+	{derbyTeam{100, "Roma"}, derbyTeam{110, "Lazio"}, 0.28, "Римское дерби"},
+	{derbyTeam{109, "Juventus"}, derbyTeam{586, "Torino"}, 0.2, "Дерби делла Моле"},
+	{derbyTeam{107, "Genoa"}, derbyTeam{584, "Sampdoria"}, 0.18, "Дерби делла Лантерна"},
+	{derbyTeam{113, "Napoli"}, derbyTeam{100, "Roma"}, 0.15, ""},
+
+	// Франция (Ligue1)
+	{derbyTeam{524, "PSG"}, derbyTeam{516, "Marseille"}, 0.23, "Ле Классик"},
+	{derbyTeam{523, "Olympique Lyon"}, derbyTeam{527, "Saint-Etienne"}, 0.18, "Ронское дерби"},
+	{derbyTeam{522, "Nice"}, derbyTeam{548, "Monaco"}, 0.14, "Лазурное дерби"},
+	{derbyTeam{521, "Lille"}, derbyTeam{546, "RC Lens"}, 0.14, "Северное дерби"},
+}
+
+// Переносит дерби из старого списка в коллекцию rivalries
+// Уже существующие пары не перезаписываются, чтобы не потерять правки администраторов
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	mongoURI := os.Getenv("MONGODB_URI")
+	if mongoURI == "" {
+		log.Fatal("MONGODB_URI is not set in the .env file")
+	}
+
+	ctx := context.Background()
+	client, err := db.ConnectToMongoDB(mongoURI)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Disconnect(context.Background())
+
+	rivalryService := service.NewRivalryService(
+		mongoRepo.NewMongoDBRivalryStore(client, "football"),
+		mongoRepo.NewMongoDBTeamsStore(client, "football"),
+	)
+
+	var saved, skipped int
+	for _, d := range legacyDerbys {
+		existing, err := rivalryService.HandleGetRivalry(ctx, d.first.id, d.second.id)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if existing != nil {
+			log.Printf("Skipping %s - %s: already in the registry", d.first.name, d.second.name)
+			skipped++
+			continue
+		}
+
+		rivalry := types.Rivalry{
+			TeamA:     d.first.id,
+			TeamB:     d.second.id,
+			TeamAName: d.first.name,
+			TeamBName: d.second.name,
+			Intensity: d.intensity,
+			Notes:     d.notes,
+		}
+		if err := rivalryService.HandleSaveRivalry(ctx, rivalry); err != nil {
+			log.Printf("Error saving %s - %s: %v", d.first.name, d.second.name, err)
+			skipped++
+			continue
+		}
+		saved++
+	}
+	log.Printf("Seeded %d rivalries, skipped %d", saved, skipped)
+}
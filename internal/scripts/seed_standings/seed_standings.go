@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
-	"time"
 
 	apiClient "github.com/vsespontanno/tgbot_fschedule/internal/client"
+	"github.com/vsespontanno/tgbot_fschedule/internal/config"
 	"github.com/vsespontanno/tgbot_fschedule/internal/db"
 	mongoRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
+	"github.com/vsespontanno/tgbot_fschedule/internal/service"
 	"github.com/vsespontanno/tgbot_fschedule/internal/tools"
 	"github.com/vsespontanno/tgbot_fschedule/internal/types"
 
@@ -22,9 +22,9 @@ func main() {
 	if err != nil {
 		log.Fatalf("Error loading .env file: %v", err)
 	}
-	apiKey := os.Getenv("FOOTBALL_DATA_API_KEY")
-	if apiKey == "" {
-		log.Fatal("FOOTBALL_DATA_API_KEY is not set in the .env file")
+	footallClient, err := apiClient.NewFootballData(os.Getenv("FOOTBALL_DATA_API_KEY"), os.Getenv("FOOTBALL_DATA_REPLAY_DIR"), os.Getenv("FOOTBALL_DATA_RECORD_DIR"), apiClient.NewRateLimiter(config.LoadConfig().UpdaterRequestsPerMinute))
+	if err != nil {
+		log.Fatal(err)
 	}
 	mongoURI := os.Getenv("MONGODB_URI")
 	if mongoURI == "" {
@@ -38,23 +38,23 @@ func main() {
 	defer client.Disconnect(context.TODO())
 
 	store := mongoRepo.NewMongoDBStandingsStore(client, "football")
-	footallClient := apiClient.NewFootballAPIClient(&http.Client{}, apiKey)
+	seasonService := service.NewSeasonService(mongoRepo.NewMongoDBSeasonStore(client, "football"), footallClient)
+	if _, err := seasonService.HandleRefreshSeasons(context.Background()); err != nil {
+		log.Printf("Failed to refresh some seasons, falling back to the calendar: %v\n", err)
+	}
 	for leagueName, league := range types.Leagues {
-		var standings []types.Standing
-		var err error
-		for i := 0; i < 3; i++ { // Retry up to 3 times
-			if league.Code == "CL" {
-				break
-			}
-			standings, err = footallClient.FetchStandings(context.Background(), league.Code)
-			if err == nil {
-				break // Success, exit retry loop
-			}
-			log.Printf("Attempt %d: Error getting standings for %s: %v\n", i+1, leagueName, err)
-			time.Sleep(2 * time.Second) // Wait for 2 seconds before retrying
+		if league.Code == "CL" {
+			continue
+		}
+		// Повторы после 429 и ошибок сервера выполняет сам клиент
+		season, err := seasonService.HandleGetActiveSeason(context.Background(), league.Code)
+		if err != nil {
+			log.Printf("Failed to get season for %s: %v\n", leagueName, err)
+			continue
 		}
+		standings, err := footallClient.FetchStandings(context.Background(), league.Code, season)
 		if err != nil {
-			log.Printf("Failed to get standings for %s after multiple retries: %v\n", leagueName, err)
+			log.Printf("Failed to get standings for %s: %v\n", leagueName, err)
 			continue
 		}
 
@@ -4,10 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 
 	"github.com/vsespontanno/tgbot_fschedule/internal/client"
+	"github.com/vsespontanno/tgbot_fschedule/internal/config"
 	"github.com/vsespontanno/tgbot_fschedule/internal/db"
 	mongoRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
 	"github.com/vsespontanno/tgbot_fschedule/internal/service"
@@ -29,15 +29,11 @@ func main() {
 		log.Fatal("MONGODB_URI is not set in the .env file")
 	}
 
-	apiKey := os.Getenv("FOOTBALL_DATA_API_KEY")
-	if apiKey == "" {
-		log.Fatal("FOOTBALL_DATA_API_KEY is not set in the .env file")
+	apiClient, err := client.NewFootballData(os.Getenv("FOOTBALL_DATA_API_KEY"), os.Getenv("FOOTBALL_DATA_REPLAY_DIR"), os.Getenv("FOOTBALL_DATA_RECORD_DIR"), client.NewRateLimiter(config.LoadConfig().UpdaterRequestsPerMinute))
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	httpClient := &http.Client{}
-
-	apiClient := client.NewFootballAPIClient(httpClient, apiKey)
-
 	client, err := db.ConnectToMongoDB(mongoURI)
 	if err != nil {
 		log.Fatal(err)
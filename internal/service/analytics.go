@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pgRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/postgres"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Размер буфера событий; при переполнении новые события отбрасываются,
+	// чтобы аналитика никогда не тормозила обработку апдейтов
+	analyticsBufferSize = 1024
+	// Сколько событий пишется одним INSERT
+	analyticsBatchSize = 100
+	// Как часто сбрасывается неполная пачка
+	analyticsFlushInterval = 5 * time.Second
+)
+
+// AnalyticsService собирает события использования бота и пишет их в PostgreSQL пачками
+// Реализует PersonalDataStore: выгрузка и удаление учитывают ещё не записанные события
+type AnalyticsService struct {
+	eventStore pgRepo.EventStore
+	events     chan types.Event
+	requests   chan userEventsRequest
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// Запрос к фоновой записи по событиям пользователя: сбросить их в базу или отбросить
+type userEventsRequest struct {
+	telegramID int64
+	drop       bool
+	handled    chan struct{}
+}
+
+// Конструктор для создания нового экземпляра AnalyticsService
+// Запускает фоновую горутину записи; остановить её нужно через Close
+func NewAnalyticsService(eventStore pgRepo.EventStore) *AnalyticsService {
+	s := &AnalyticsService{
+		eventStore: eventStore,
+		events:     make(chan types.Event, analyticsBufferSize),
+		requests:   make(chan userEventsRequest),
+		done:       make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Метод для записи события; не блокирует вызывающего
+func (s *AnalyticsService) Track(event types.Event) {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	select {
+	case s.events <- event:
+	default:
+		logrus.Warn("Analytics buffer is full, dropping event")
+	}
+}
+
+// Метод для получения агрегированного отчёта за день
+func (s *AnalyticsService) HandleGetDailyReport(ctx context.Context, day time.Time) (*types.UsageReport, error) {
+	return s.eventStore.GetDailyReport(ctx, day)
+}
+
+// Section возвращает название раздела событий в JSON-выгрузке
+func (s *AnalyticsService) Section() string {
+	return s.eventStore.Section()
+}
+
+// ExportUserData выгружает события пользователя, предварительно записав накопленные
+func (s *AnalyticsService) ExportUserData(ctx context.Context, telegramID int64) (interface{}, error) {
+	if err := s.handleUserEvents(ctx, telegramID, false); err != nil {
+		return nil, err
+	}
+	return s.eventStore.ExportUserData(ctx, telegramID)
+}
+
+// DeleteUserData отбрасывает ещё не записанные события пользователя и удаляет записанные,
+// чтобы после удаления данных из буфера не дописались, например, событие самой команды /delete_me
+func (s *AnalyticsService) DeleteUserData(ctx context.Context, telegramID int64) error {
+	if err := s.handleUserEvents(ctx, telegramID, true); err != nil {
+		return err
+	}
+	return s.eventStore.DeleteUserData(ctx, telegramID)
+}
+
+// Передаёт фоновой записи запрос по событиям пользователя и ждёт его выполнения
+func (s *AnalyticsService) handleUserEvents(ctx context.Context, telegramID int64, drop bool) error {
+	req := userEventsRequest{telegramID: telegramID, drop: drop, handled: make(chan struct{})}
+	select {
+	case s.requests <- req:
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-req.handled:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close сбрасывает накопленные события и останавливает фоновую запись
+func (s *AnalyticsService) Close() {
+	s.closeOnce.Do(func() {
+		close(s.events)
+		<-s.done
+	})
+}
+
+// Фоновая запись: пачка сбрасывается по размеру или по таймеру
+func (s *AnalyticsService) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(analyticsFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]types.Event, 0, analyticsBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.eventStore.SaveEvents(ctx, batch); err != nil {
+			logrus.Errorf("Failed to save %d analytics events: %v", len(batch), err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case event, ok := <-s.events:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= analyticsBatchSize {
+				flush()
+			}
+		case req := <-s.requests:
+			// События, попавшие в буфер до запроса, тоже должны быть учтены
+			for pending := len(s.events); pending > 0; pending-- {
+				batch = append(batch, <-s.events)
+			}
+			if req.drop {
+				batch = withoutUserEvents(batch, req.telegramID)
+			}
+			flush()
+			close(req.handled)
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// События пачки без событий пользователя telegramID
+func withoutUserEvents(batch []types.Event, telegramID int64) []types.Event {
+	kept := batch[:0]
+	for _, event := range batch {
+		if event.TelegramID != telegramID {
+			kept = append(kept, event)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	pgRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/postgres"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Хранилище событий в памяти
+type memoryEventStore struct {
+	pgRepo.EventStore
+	mu     sync.Mutex
+	events []types.Event
+}
+
+func (m *memoryEventStore) SaveEvents(ctx context.Context, events []types.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, events...)
+	return nil
+}
+
+func (m *memoryEventStore) DeleteUserData(ctx context.Context, telegramID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = withoutUserEvents(m.events, telegramID)
+	return nil
+}
+
+func TestAnalyticsDeleteDropsPendingEvents(t *testing.T) {
+	store := &memoryEventStore{}
+	s := NewAnalyticsService(store)
+	s.Track(types.Event{TelegramID: 1, Name: "/start"})
+	s.Track(types.Event{TelegramID: 2, Name: "/start"})
+	s.Track(types.Event{TelegramID: 1, Name: "/delete_me"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.DeleteUserData(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	if len(store.events) != 1 || store.events[0].TelegramID != 2 {
+		t.Errorf("stored events = %+v, want only the event of user 2", store.events)
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+const (
+	// Название строки отчёта по всем лигам сразу
+	BacktestAllLeagues = "ALL"
+	// Сколько голов считается максимально зрелищным матчем в сводной оценке
+	excitementGoalsCap = 5
+)
+
+// BacktestCandidate - стратегия с профилем, рейтинги которой сравниваются с исходами матчей
+type BacktestCandidate struct {
+	Name     string
+	Strategy RatingStrategy
+}
+
+// BacktestRow - ранговые корреляции Спирмена рейтинга с показателями зрелищности матча
+// NaN означает, что корреляцию посчитать нельзя (мало матчей или нет разброса)
+type BacktestRow struct {
+	Candidate string
+	League    string
+	Matches   int
+	// Всего голов в матче
+	Goals float64
+	// Исход решился во втором тайме: победитель не вёл после первого тайма
+	Late float64
+	// Победила команда, которая до матча была ниже в таблице (или слабее по Эло)
+	Upset float64
+	// Сводная оценка зрелищности из трёх показателей
+	Excitement float64
+}
+
+// Показатели зрелищности сыгранного матча
+type excitement struct {
+	goals int
+	late  bool
+	upset bool
+}
+
+func (e excitement) score() float64 {
+	score := math.Min(float64(e.goals), excitementGoalsCap) / excitementGoalsCap
+	if e.late {
+		score++
+	}
+	if e.upset {
+		score++
+	}
+	return score / 3
+}
+
+// Рейтинг матча одного кандидата вместе с исходом
+type backtestSample struct {
+	league  string
+	rating  float64
+	outcome excitement
+}
+
+// Backtest прогоняет сыгранные матчи в хронологическом порядке и считает рейтинг каждым кандидатом
+// по данным, известным до начала матча. Матчи раньше since только накапливают историю и в отчёт не попадают
+// Возвращает строки по каждому кандидату и лиге, включая строку BacktestAllLeagues
+func Backtest(ctx context.Context, matches []types.Match, rivalries []types.Rivalry, candidates []BacktestCandidate, since string) []BacktestRow {
+	finished := newFinishedMatches(matches, types.EloState{})
+	calculator := NewReplayCalculator(finished, rivalries)
+	samples := make(map[string][]backtestSample, len(candidates))
+
+	for start := 0; start < len(finished); {
+		// Матчи с одним временем начала считаются одновременными: ни один не видит результатов другого
+		end := start
+		for end < len(finished) && finished[end].UTCDate == finished[start].UTCDate {
+			end++
+		}
+		calculator.Kickoff(finished[start].UTCDate)
+		if finished[start].UTCDate >= since {
+			for _, match := range finished[start:end] {
+				outcome := matchExcitement(match, calculator.favourite(ctx, match))
+				for _, candidate := range candidates {
+					breakdown, err := candidate.Strategy.Rate(ctx, match, calculator)
+					if err != nil {
+						continue
+					}
+					samples[candidate.Name] = append(samples[candidate.Name], backtestSample{
+						league:  match.Competition.Name,
+						rating:  breakdown.Rating,
+						outcome: outcome,
+					})
+				}
+			}
+		}
+		for _, match := range finished[start:end] {
+			calculator.Observe(match)
+		}
+		start = end
+	}
+
+	var rows []BacktestRow
+	for _, candidate := range candidates {
+		byLeague := map[string][]backtestSample{BacktestAllLeagues: samples[candidate.Name]}
+		for _, s := range samples[candidate.Name] {
+			byLeague[s.league] = append(byLeague[s.league], s)
+		}
+		leagues := make([]string, 0, len(byLeague))
+		for league := range byLeague {
+			leagues = append(leagues, league)
+		}
+		sort.Strings(leagues)
+		for _, league := range leagues {
+			rows = append(rows, backtestRow(candidate.Name, league, byLeague[league]))
+		}
+	}
+	return rows
+}
+
+// Считает корреляции для набора матчей
+func backtestRow(candidate, league string, samples []backtestSample) BacktestRow {
+	n := len(samples)
+	ratings := make([]float64, n)
+	goals := make([]float64, n)
+	late := make([]float64, n)
+	upset := make([]float64, n)
+	total := make([]float64, n)
+	for i, s := range samples {
+		ratings[i] = s.rating
+		goals[i] = float64(s.outcome.goals)
+		late[i] = boolFloat(s.outcome.late)
+		upset[i] = boolFloat(s.outcome.upset)
+		total[i] = s.outcome.score()
+	}
+	return BacktestRow{
+		Candidate:  candidate,
+		League:     league,
+		Matches:    n,
+		Goals:      Spearman(ratings, goals),
+		Late:       Spearman(ratings, late),
+		Upset:      Spearman(ratings, upset),
+		Excitement: Spearman(ratings, total),
+	}
+}
+
+// Показатели зрелищности матча; favourite - фаворит до начала (1 - хозяева, -1 - гости, 0 - неизвестно)
+func matchExcitement(match types.Match, favourite int) excitement {
+	ft := match.Score.FullTime
+	result := sign(ft.Home - ft.Away)
+	return excitement{
+		goals: ft.Home + ft.Away,
+		late:  lateSwing(match),
+		upset: favourite != 0 && result == -favourite,
+	}
+}
+
+// Решился ли исход во втором тайме: победитель по итогам матча не вёл после первого тайма
+// Для матчей без счёта первого тайма - false
+func lateSwing(match types.Match) bool {
+	ft, ht := match.Score.FullTime, match.Score.HalfTime
+	result := sign(ft.Home - ft.Away)
+	return ht != nil && result != 0 && sign(ht.Home-ht.Away) != result
+}
+
+func sign(x int) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Spearman возвращает ранговую корреляцию Спирмена; одинаковые значения получают средний ранг
+// Возвращает NaN, если значений меньше трёх или у одного из рядов нет разброса
+func Spearman(x, y []float64) float64 {
+	if len(x) != len(y) || len(x) < 3 {
+		return math.NaN()
+	}
+	return pearson(ranks(x), ranks(y))
+}
+
+// Ранги значений начиная с 1 со средним рангом для одинаковых значений
+func ranks(values []float64) []float64 {
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return values[idx[a]] < values[idx[b]] })
+	result := make([]float64, len(values))
+	for start := 0; start < len(idx); {
+		end := start
+		for end < len(idx) && values[idx[end]] == values[idx[start]] {
+			end++
+		}
+		rank := float64(start+end+1) / 2
+		for _, i := range idx[start:end] {
+			result[i] = rank
+		}
+		start = end
+	}
+	return result
+}
+
+func pearson(x, y []float64) float64 {
+	var meanX, meanY float64
+	for i := range x {
+		meanX += x[i]
+		meanY += y[i]
+	}
+	meanX /= float64(len(x))
+	meanY /= float64(len(y))
+	var cov, varX, varY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(varX*varY)
+}
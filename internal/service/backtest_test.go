@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+func TestSpearman(t *testing.T) {
+	if got := Spearman([]float64{1, 2, 3, 4}, []float64{10, 20, 30, 40}); math.Abs(got-1) > 1e-9 {
+		t.Errorf("monotonic series should correlate perfectly, got %.3f", got)
+	}
+	if got := Spearman([]float64{1, 2, 3, 4}, []float64{4, 3, 2, 1}); math.Abs(got+1) > 1e-9 {
+		t.Errorf("reversed series should correlate negatively, got %.3f", got)
+	}
+	if got := Spearman([]float64{1, 2, 3}, []float64{1, 1, 1}); !math.IsNaN(got) {
+		t.Errorf("constant series should give NaN, got %.3f", got)
+	}
+	if got := ranks([]float64{5, 1, 5, 3}); got[0] != 3.5 || got[1] != 1 || got[2] != 3.5 || got[3] != 2 {
+		t.Errorf("ties should share the average rank, got %v", got)
+	}
+}
+
+func TestReplayCalculatorSeesOnlyPastResults(t *testing.T) {
+	ctx := context.Background()
+	first := playedMatch(1, "2024-08-10T14:00:00Z", 1, 2, 3, 0)
+	second := playedMatch(2, "2024-08-17T14:00:00Z", 2, 1, 0, 0)
+	c := NewReplayCalculator([]types.Match{first, second}, nil)
+
+	c.Kickoff(first.UTCDate)
+	if pos, _ := c.HandleGetTeamStanding(ctx, "PremierLeague", 1); pos != -1 {
+		t.Errorf("before the first result the table should be empty, got position %d", pos)
+	}
+	if elo, _ := c.HandleGetTeamElo(ctx, 1); elo != EloInitial {
+		t.Errorf("elo should not move before the match, got %.1f", elo)
+	}
+
+	c.Observe(first)
+	c.Kickoff(second.UTCDate)
+	if pos, _ := c.HandleGetTeamStanding(ctx, "PremierLeague", 1); pos != 1 {
+		t.Errorf("the winner should lead the table, got position %d", pos)
+	}
+	if recent, _ := c.HandleGetRecentMatches(ctx, 2, FormMatches); len(recent) != 1 || recent[0].ID != 1 {
+		t.Errorf("only the first match should be in the form, got %+v", recent)
+	}
+	if c.favourite(ctx, second) != -1 {
+		t.Error("the away leader should be the favourite")
+	}
+
+	// Новый сезон начинается с пустой таблицы
+	c.Kickoff("2025-08-16T14:00:00Z")
+	if standings, _ := c.HandleGetStandings(ctx, "PremierLeague"); len(standings) != 0 {
+		t.Errorf("a new season should start with an empty table, got %d rows", len(standings))
+	}
+}
+
+func TestMatchExcitement(t *testing.T) {
+	m := playedMatch(1, "2024-08-10T14:00:00Z", 1, 2, 2, 3)
+	m.Score.HalfTime = &types.HalfTimeScore{Home: 1, Away: 0}
+	e := matchExcitement(m, 1)
+	if e.goals != 5 || !e.late || !e.upset {
+		t.Errorf("a comeback win by the underdog should be late and an upset, got %+v", e)
+	}
+	if e.score() != 1 {
+		t.Errorf("maximum excitement should score 1, got %.2f", e.score())
+	}
+}
+
+func TestBacktestRows(t *testing.T) {
+	var matches []types.Match
+	dates := []string{"2024-08-10T14:00:00Z", "2024-08-17T14:00:00Z", "2024-08-24T14:00:00Z", "2024-08-31T14:00:00Z"}
+	for i, date := range dates {
+		matches = append(matches, playedMatch(i*2+1, date, 1, 2, i, 1), playedMatch(i*2+2, date, 3, 4, 1, i))
+	}
+	profiles, _ := NewRatingProfiles("")
+	rows := Backtest(context.Background(), matches, nil, []BacktestCandidate{{Name: "heuristic", Strategy: NewHeuristicStrategy(profiles)}}, "")
+	if len(rows) != 2 || rows[0].League != BacktestAllLeagues || rows[1].League != "EPL" {
+		t.Fatalf("expected ALL and EPL rows, got %+v", rows)
+	}
+	if rows[0].Matches != len(matches) {
+		t.Errorf("every match should be rated, got %d of %d", rows[0].Matches, len(matches))
+	}
+}
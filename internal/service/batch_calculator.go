@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Коллекция со всеми командами лиг, по которой определяется лига команды
+const teamsCollection = "Teams"
+
+// Preloader реализуют калькуляторы, которые умеют заранее загрузить данные для списка матчей
+type Preloader interface {
+	Preload(ctx context.Context, matches []types.Match) (Calculator, error)
+}
+
+// BatchCalculator - Calculator с данными, загруженными заранее для списка матчей
+// Отвечает из памяти и не ходит в базу, поэтому число запросов не зависит от числа матчей
+type BatchCalculator struct {
+	leagues   map[int]string
+	standings map[string][]types.Standing
+	recent    map[int][]types.Match
+	elo       map[int]float64
+	rivalries map[[2]int]float64
+	knockouts []types.Match
+	// Прогнозы модели Пуассона по ID матча
+	predictions map[int]*types.Prediction
+}
+
+// Preload загружает команды, таблицы, последние матчи, Эло, дерби, первые кубковые матчи
+// и прогнозы для всех команд из списка матчей несколькими запросами
+func (a *CalculatorAdapter) Preload(ctx context.Context, matches []types.Match) (Calculator, error) {
+	teamIDs := matchTeamIDs(matches)
+	c := &BatchCalculator{
+		leagues:     make(map[int]string, len(teamIDs)),
+		standings:   make(map[string][]types.Standing),
+		elo:         make(map[int]float64, len(teamIDs)),
+		rivalries:   make(map[[2]int]float64),
+		predictions: make(map[int]*types.Prediction, len(matches)),
+	}
+	if len(teamIDs) == 0 {
+		return c, nil
+	}
+
+	teams, err := a.teamsStore.GetTeamsByIDs(ctx, teamsCollection, teamIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error preloading teams: %w", err)
+	}
+	for _, t := range teams {
+		c.leagues[t.ID] = t.League
+	}
+
+	for _, league := range c.leagues {
+		if _, ok := c.standings[league]; ok {
+			continue
+		}
+		standings, err := a.standingsStore.GetStandings(ctx, league)
+		if err != nil {
+			return nil, fmt.Errorf("error preloading standings for %s: %w", league, err)
+		}
+		c.standings[league] = standings
+	}
+
+	// Матчей берётся столько, сколько нужно для зрелищности; форма использует первые из них
+	c.recent, err = a.matchesStore.GetRecentMatchesForTeams(ctx, teamIDs, EntertainmentMatches)
+	if err != nil {
+		return nil, fmt.Errorf("error preloading recent matches: %w", err)
+	}
+
+	elos, err := a.eloStore.GetTeamElosByIDs(ctx, teamIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error preloading elo: %w", err)
+	}
+	for _, e := range elos {
+		c.elo[e.TeamID] = e.Rating
+	}
+
+	rivalries, err := a.rivalryStore.GetRivalries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error preloading rivalries: %w", err)
+	}
+	for _, r := range rivalries {
+		c.rivalries[[2]int{r.TeamA, r.TeamB}] = r.Intensity
+	}
+
+	competitions, stages := knockoutStages(matches)
+	if len(stages) > 0 {
+		c.knockouts, err = a.matchesStore.GetFinishedMatchesByStage(ctx, competitions, stages, teamIDs)
+		if err != nil {
+			return nil, fmt.Errorf("error preloading knockout matches: %w", err)
+		}
+	}
+
+	if a.predictor != nil {
+		for _, m := range matches {
+			c.predictions[m.ID], err = a.predictor.HandleGetPrediction(ctx, m)
+			if err != nil {
+				return nil, fmt.Errorf("error preloading prediction for match %d: %w", m.ID, err)
+			}
+		}
+	}
+	return c, nil
+}
+
+// Уникальные ID команд из списка матчей
+func matchTeamIDs(matches []types.Match) []int {
+	seen := make(map[int]bool)
+	var ids []int
+	for _, m := range matches {
+		for _, id := range []int{m.HomeTeam.ID, m.AwayTeam.ID} {
+			if id != 0 && !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// Турниры и стадии матчей плей-офф из списка, для которых может понадобиться первый матч
+func knockoutStages(matches []types.Match) (competitions []int, stages []string) {
+	seenCompetitions := make(map[int]bool)
+	seenStages := make(map[string]bool)
+	for _, m := range matches {
+		if m.Stage == "" || m.Stage == regularSeasonStage {
+			continue
+		}
+		if !seenCompetitions[m.Competition.ID] {
+			seenCompetitions[m.Competition.ID] = true
+			competitions = append(competitions, m.Competition.ID)
+		}
+		if !seenStages[m.Stage] {
+			seenStages[m.Stage] = true
+			stages = append(stages, m.Stage)
+		}
+	}
+	return competitions, stages
+}
+
+// Лига команды из загруженных данных; для неизвестной команды - ошибка, как у хранилища
+func (c *BatchCalculator) HandleGetLeague(ctx context.Context, collectionName string, teamID int) (string, error) {
+	league, ok := c.leagues[teamID]
+	if !ok || collectionName != teamsCollection {
+		return "", fmt.Errorf("team with ID %d is not preloaded from %s", teamID, collectionName)
+	}
+	return league, nil
+}
+
+// Место команды в таблице; -1, если команды в таблице нет
+func (c *BatchCalculator) HandleGetTeamStanding(ctx context.Context, leagueKey string, teamID int) (int, error) {
+	for _, s := range c.standings[leagueKey] {
+		if s.Team.ID == teamID {
+			return s.Position, nil
+		}
+	}
+	return -1, nil
+}
+
+func (c *BatchCalculator) HandleGetStandings(ctx context.Context, leagueKey string) ([]types.Standing, error) {
+	return c.standings[leagueKey], nil
+}
+
+func (c *BatchCalculator) HandleGetRecentMatches(ctx context.Context, teamID int, lastN int) ([]types.Match, error) {
+	recent := c.recent[teamID]
+	if len(recent) > lastN {
+		recent = recent[:lastN]
+	}
+	return recent, nil
+}
+
+// Первый матч противостояния среди загруженных кубковых матчей
+func (c *BatchCalculator) HandleGetFirstLeg(ctx context.Context, match types.Match) (*types.Match, error) {
+	var firstLeg *types.Match
+	for i := range c.knockouts {
+		m := &c.knockouts[i]
+		if m.Competition.ID != match.Competition.ID || m.Stage != match.Stage ||
+			m.HomeTeam.ID != match.AwayTeam.ID || m.AwayTeam.ID != match.HomeTeam.ID ||
+			m.UTCDate >= match.UTCDate {
+			continue
+		}
+		if firstLeg == nil || m.UTCDate > firstLeg.UTCDate {
+			firstLeg = m
+		}
+	}
+	return firstLeg, nil
+}
+
+func (c *BatchCalculator) HandleGetRivalryIntensity(ctx context.Context, homeTeamID, awayTeamID int) (float64, error) {
+	teamA, teamB := types.RivalryPair(homeTeamID, awayTeamID)
+	return c.rivalries[[2]int{teamA, teamB}], nil
+}
+
+func (c *BatchCalculator) HandleGetTeamElo(ctx context.Context, teamID int) (float64, error) {
+	if elo, ok := c.elo[teamID]; ok {
+		return elo, nil
+	}
+	return EloInitial, nil
+}
+
+func (c *BatchCalculator) HandleGetPrediction(ctx context.Context, match types.Match) (*types.Prediction, error) {
+	return c.predictions[match.ID], nil
+}
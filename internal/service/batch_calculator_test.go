@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+func TestBatchCalculatorLookups(t *testing.T) {
+	ctx := context.Background()
+	var firstLeg, secondLeg types.Match
+	firstLeg.Competition.ID, firstLeg.Stage, firstLeg.UTCDate = 2001, "LAST_16", "2025-03-04T20:00:00Z"
+	firstLeg.HomeTeam.ID, firstLeg.AwayTeam.ID = 2, 1
+	secondLeg.Competition.ID, secondLeg.Stage, secondLeg.UTCDate = 2001, "LAST_16", "2025-03-11T20:00:00Z"
+	secondLeg.HomeTeam.ID, secondLeg.AwayTeam.ID = 1, 2
+
+	standing := types.Standing{Position: 3}
+	standing.Team.ID = 1
+	c := &BatchCalculator{
+		leagues:   map[int]string{1: "LaLiga"},
+		standings: map[string][]types.Standing{"LaLiga": {standing}},
+		elo:       map[int]float64{1: 1720},
+		rivalries: map[[2]int]float64{{1, 2}: 0.3},
+		knockouts: []types.Match{firstLeg},
+	}
+
+	if league, err := c.HandleGetLeague(ctx, teamsCollection, 1); err != nil || league != "LaLiga" {
+		t.Errorf("HandleGetLeague = %q, %v", league, err)
+	}
+	if _, err := c.HandleGetLeague(ctx, teamsCollection, 2); err == nil {
+		t.Error("expected an error for a team that is not preloaded")
+	}
+	if pos, _ := c.HandleGetTeamStanding(ctx, "LaLiga", 1); pos != 3 {
+		t.Errorf("HandleGetTeamStanding = %d, want 3", pos)
+	}
+	if pos, _ := c.HandleGetTeamStanding(ctx, "LaLiga", 2); pos != -1 {
+		t.Errorf("HandleGetTeamStanding for a missing team = %d, want -1", pos)
+	}
+	if elo, _ := c.HandleGetTeamElo(ctx, 2); elo != EloInitial {
+		t.Errorf("HandleGetTeamElo for an unrated team = %.0f, want %.0f", elo, EloInitial)
+	}
+	if bonus, _ := c.HandleGetRivalryIntensity(ctx, 2, 1); bonus != 0.3 {
+		t.Errorf("HandleGetRivalryIntensity = %.2f, want 0.3", bonus)
+	}
+	if leg, _ := c.HandleGetFirstLeg(ctx, secondLeg); leg == nil || leg.UTCDate != firstLeg.UTCDate {
+		t.Errorf("HandleGetFirstLeg = %+v, want the first leg", leg)
+	}
+	if leg, _ := c.HandleGetFirstLeg(ctx, firstLeg); leg != nil {
+		t.Errorf("a first leg should have no first leg, got %+v", leg)
+	}
+}
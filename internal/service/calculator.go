@@ -8,23 +8,6 @@ import (
 	"github.com/vsespontanno/tgbot_fschedule/internal/types"
 )
 
-// CalculateForm вычисляет форму команды на основе последних матчей
-func CalculateForm(matches []types.Match, teamID int) float64 {
-	if len(matches) == 0 {
-		return 0.5 // Нейтральная форма, если данных нет
-	}
-	var wins int
-	for _, m := range matches {
-		if m.Status == "FINISHED" {
-			if (m.Score.Winner == "HOME_TEAM" && m.HomeTeam.ID == teamID) ||
-				(m.Score.Winner == "AWAY_TEAM" && m.AwayTeam.ID == teamID) {
-				wins++
-			}
-		}
-	}
-	return float64(wins) / float64(len(matches))
-}
-
 // GetLeaguesForTeams определяет лиги для команд
 func GetLeaguesForTeams(ctx context.Context, calculator Calculator, homeTeamID int, awayTeamID int) (homeLeague string, awayLeague string, err error) {
 	homeLeague, err = calculator.HandleGetLeague(ctx, "Teams", homeTeamID)
@@ -60,38 +43,26 @@ func CalculatePositionOfTeams(ctx context.Context, calculator Calculator, match
 		return -1, -1, fmt.Errorf("error getting away team standing: %s", err)
 	}
 
-	homeTeamRating := (float64(types.TeamsInLeague[HomeLeague] - posHome)) / float64(types.TeamsInLeague[HomeLeague]-1)
-	awayTeamRating := (float64(types.TeamsInLeague[AwayLeague] - posAway)) / float64(types.TeamsInLeague[AwayLeague]-1)
-	return homeTeamRating, awayTeamRating, nil
+	return positionStrength(HomeLeague, posHome), positionStrength(AwayLeague, posAway), nil
 }
 
-// GetDerbyBonus вычисляет бонус за дерби между командами
-// Использует короткие названия команд для поиска в словаре дерби
-// Если бонус не найден, возвращает 0
-func GetDerbyBonus(ctx context.Context, calculator Calculator, match types.Match) float64 {
-	homeLeague, awayLeague, err := GetLeaguesForTeams(ctx, calculator, match.HomeTeam.ID, match.AwayTeam.ID)
-	if err != nil {
-		log.Printf("Error getting leagues for derby bonus: %v", err)
-		return 0.0
-	}
-	homeShortName, err := calculator.HandleGetTeamShortName(ctx, homeLeague, match.HomeTeam.Name)
-	if err != nil {
-		log.Printf("Error getting short name for home team: %v", err)
-		return 0.0
+// Переводит место в таблице в силу от 0 до 1 (лидер - 1, последнее место - 0)
+// Для команд без места в таблице или лиг без таблицы (например, Лига чемпионов) возвращает среднюю силу
+func positionStrength(league string, position int) float64 {
+	teams := types.TeamsInLeague[league]
+	if position < 1 || teams < 2 {
+		return neutralStrength
 	}
-	awayShortName, err := calculator.HandleGetTeamShortName(ctx, awayLeague, match.AwayTeam.Name)
+	return float64(teams-position) / float64(teams-1)
+}
+
+// GetDerbyBonus вычисляет бонус за дерби между командами по реестру дерби
+// Если пара не дерби или реестр недоступен, возвращает 0
+func GetDerbyBonus(ctx context.Context, calculator Calculator, match types.Match) float64 {
+	bonus, err := calculator.HandleGetRivalryIntensity(ctx, match.HomeTeam.ID, match.AwayTeam.ID)
 	if err != nil {
-		log.Printf("Error getting short name for away team: %v", err)
+		log.Printf("Error getting derby bonus: %v", err)
 		return 0.0
 	}
-	key := [2]string{homeShortName, awayShortName}
-	reverseKey := [2]string{awayShortName, homeShortName}
-
-	if bonus, ok := types.Derbys[key]; ok {
-		return bonus
-	}
-	if bonus, ok := types.Derbys[reverseKey]; ok {
-		return bonus
-	}
-	return 0.0
+	return bonus
 }
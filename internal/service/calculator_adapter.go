@@ -13,11 +13,15 @@ type CalculatorAdapter struct {
 	teamsStore     mongoRepo.TeamsCalcStore
 	standingsStore mongoRepo.StandingsCalcStore
 	matchesStore   mongoRepo.MatchCalcStore
+	eloStore       mongoRepo.EloCalcStore
+	rivalryStore   mongoRepo.RivalryCalcStore
+	predictor      Predictor
 }
 
 // Конструктор для создания нового экземпляра CalculatorAdapter
-func NewCalculatorAdapter(teamsStore mongoRepo.TeamsCalcStore, standingsStore mongoRepo.StandingsCalcStore, matchesStore mongoRepo.MatchCalcStore) Calculator {
-	return &CalculatorAdapter{teamsStore, standingsStore, matchesStore}
+// predictor может быть nil: тогда прогнозов нет и стратегии обходятся без них
+func NewCalculatorAdapter(teamsStore mongoRepo.TeamsCalcStore, standingsStore mongoRepo.StandingsCalcStore, matchesStore mongoRepo.MatchCalcStore, eloStore mongoRepo.EloCalcStore, rivalryStore mongoRepo.RivalryCalcStore, predictor Predictor) Calculator {
+	return &CalculatorAdapter{teamsStore, standingsStore, matchesStore, eloStore, rivalryStore, predictor}
 }
 
 // Находит место команды в турнирной таблице по её уникальному идентификатору
@@ -29,12 +33,24 @@ func (a *CalculatorAdapter) HandleGetTeamStanding(ctx context.Context, league st
 	return standing, nil
 }
 
+// Получает всю турнирную таблицу лиги
+// Используется для оценки турнирной значимости матча
+func (a *CalculatorAdapter) HandleGetStandings(ctx context.Context, league string) ([]types.Standing, error) {
+	return a.standingsStore.GetStandings(ctx, league)
+}
+
 // Получает последние N матчей команды по её уникальному идентификатору
 // Используется для анализа формы команды и её текущего состояния
 func (a *CalculatorAdapter) HandleGetRecentMatches(ctx context.Context, teamID, lastN int) ([]types.Match, error) {
 	return a.matchesStore.GetRecentMatches(ctx, teamID, lastN)
 }
 
+// Получает первый матч кубкового противостояния для ответного матча
+// Возвращает nil, если текущий матч не ответный или первый ещё не сыгран
+func (a *CalculatorAdapter) HandleGetFirstLeg(ctx context.Context, match types.Match) (*types.Match, error) {
+	return a.matchesStore.GetFirstLeg(ctx, match)
+}
+
 // Получает лигу команды по её уникальному идентификатору
 // Используется для определения, в какой лиге играет команда
 func (a *CalculatorAdapter) HandleGetLeague(ctx context.Context, collectionName string, id int) (string, error) {
@@ -45,12 +61,32 @@ func (a *CalculatorAdapter) HandleGetLeague(ctx context.Context, collectionName
 	return league, nil
 }
 
-// Получает короткое название команды по её полному названию
-// Используется для отображения более компактного названия команды в интерфейсе
-func (a *CalculatorAdapter) HandleGetTeamShortName(ctx context.Context, collectionName string, fullName string) (string, error) {
-	shortName, err := a.teamsStore.GetTeamsShortName(ctx, collectionName, fullName)
+// Получает накал дерби между командами или 0, если пара не дерби
+func (a *CalculatorAdapter) HandleGetRivalryIntensity(ctx context.Context, homeTeamID, awayTeamID int) (float64, error) {
+	rivalry, err := a.rivalryStore.GetRivalry(ctx, homeTeamID, awayTeamID)
+	if err != nil || rivalry == nil {
+		return 0, err
+	}
+	return rivalry.Intensity, nil
+}
+
+// Получает текущий рейтинг Эло команды
+// Для команды без сыгранных матчей возвращает стартовый рейтинг
+func (a *CalculatorAdapter) HandleGetTeamElo(ctx context.Context, teamID int) (float64, error) {
+	elo, err := a.eloStore.GetTeamElo(ctx, teamID)
 	if err != nil {
-		return "", err
+		return 0, err
+	}
+	if elo == nil {
+		return EloInitial, nil
+	}
+	return elo.Rating, nil
+}
+
+// Получает прогноз матча по модели Пуассона
+func (a *CalculatorAdapter) HandleGetPrediction(ctx context.Context, match types.Match) (*types.Prediction, error) {
+	if a.predictor == nil {
+		return nil, nil
 	}
-	return shortName, nil
+	return a.predictor.HandleGetPrediction(ctx, match)
 }
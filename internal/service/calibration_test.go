@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// go test ./internal/service -run TestRatingCalibration -update перезаписывает эталон
+var updateGolden = flag.Bool("update", false, "rewrite golden files")
+
+const calibrationGolden = "testdata/calibration.golden.json"
+
+// Calculator в памяти: все данные задаются заранее, поэтому рейтинг детерминирован
+type fakeCalculator struct {
+	leagues     map[int]string
+	standings   map[string][]types.Standing
+	recent      map[int][]types.Match
+	firstLegs   map[int]*types.Match
+	rivalries   map[[2]int]float64
+	elo         map[int]float64
+	predictions map[int]*types.Prediction
+}
+
+func (c *fakeCalculator) HandleGetLeague(ctx context.Context, collectionName string, teamID int) (string, error) {
+	league, ok := c.leagues[teamID]
+	if !ok {
+		return "", fmt.Errorf("team with ID %d not found", teamID)
+	}
+	return league, nil
+}
+
+func (c *fakeCalculator) HandleGetTeamStanding(ctx context.Context, leagueKey string, teamID int) (int, error) {
+	for _, s := range c.standings[leagueKey] {
+		if s.Team.ID == teamID {
+			return s.Position, nil
+		}
+	}
+	return -1, nil
+}
+
+func (c *fakeCalculator) HandleGetStandings(ctx context.Context, leagueKey string) ([]types.Standing, error) {
+	return c.standings[leagueKey], nil
+}
+
+func (c *fakeCalculator) HandleGetRecentMatches(ctx context.Context, teamID int, lastN int) ([]types.Match, error) {
+	recent := c.recent[teamID]
+	if len(recent) > lastN {
+		recent = recent[:lastN]
+	}
+	return recent, nil
+}
+
+func (c *fakeCalculator) HandleGetFirstLeg(ctx context.Context, match types.Match) (*types.Match, error) {
+	return c.firstLegs[match.ID], nil
+}
+
+func (c *fakeCalculator) HandleGetRivalryIntensity(ctx context.Context, homeTeamID, awayTeamID int) (float64, error) {
+	teamA, teamB := types.RivalryPair(homeTeamID, awayTeamID)
+	return c.rivalries[[2]int{teamA, teamB}], nil
+}
+
+func (c *fakeCalculator) HandleGetTeamElo(ctx context.Context, teamID int) (float64, error) {
+	if elo, ok := c.elo[teamID]; ok {
+		return elo, nil
+	}
+	return EloInitial, nil
+}
+
+func (c *fakeCalculator) HandleGetPrediction(ctx context.Context, match types.Match) (*types.Prediction, error) {
+	return c.predictions[match.ID], nil
+}
+
+// ID команд из football-data
+const (
+	teamRealMadrid = 86
+	teamBarcelona  = 81
+	teamGetafe     = 82
+	teamCelta      = 558
+	teamLuton      = 389
+	teamBurnley    = 328
+	teamArsenal    = 57
+	teamBayern     = 5
+)
+
+// ID матчей фикстур
+const (
+	fixtureClasico = iota + 1
+	fixtureMidTable
+	fixtureSixPointer
+	fixtureCLTie
+)
+
+// Таблица лиги: команды по местам, остальные места заняты командами-заглушками
+// Между соседними местами по 3 очка, поэтому гонки за титул и выживание плотные
+func fakeTable(league string, played int, placed map[int]int) []types.Standing {
+	teams := types.TeamsInLeague[league]
+	standings := make([]types.Standing, teams)
+	for i := range standings {
+		s := types.Standing{Position: i + 1, PlayedGames: played, Points: 2*played - 3*i}
+		s.Team.ID = 10000 + len(league)*100 + i
+		if id, ok := placed[i+1]; ok {
+			s.Team.ID = id
+		}
+		standings[i] = s
+	}
+	return standings
+}
+
+// Последние матчи команды по строке результатов, самый свежий первым: W - победа 2:1, D - 1:1, L - 0:1
+func fakeForm(teamID int, results string) []types.Match {
+	matches := make([]types.Match, len(results))
+	for i, r := range results {
+		var hg, ag int
+		switch r {
+		case 'W':
+			hg, ag = 2, 1
+		case 'D':
+			hg, ag = 1, 1
+		default:
+			hg, ag = 0, 1
+		}
+		date := fmt.Sprintf("2025-03-%02dT15:00:00Z", 28-i*3)
+		matches[i] = playedMatch(teamID*100+i, date, teamID, 9999, hg, ag)
+	}
+	return matches
+}
+
+// Матч фикстуры
+func fixtureMatch(id int, competition, code, stage string, home, away int, homeName, awayName string) types.Match {
+	var m types.Match
+	m.ID = id
+	m.UTCDate = "2025-04-05T19:00:00Z"
+	m.Status = "TIMED"
+	m.Competition.Name, m.Competition.Code = competition, code
+	m.Stage = stage
+	m.HomeTeam.ID, m.HomeTeam.Name = home, homeName
+	m.AwayTeam.ID, m.AwayTeam.Name = away, awayName
+	return m
+}
+
+// Мир для калибровки: два гранда Испании, середина Ла Лиги, низ АПЛ и четвертьфинал ЛЧ
+func calibrationWorld() *fakeCalculator {
+	firstLeg := fixtureMatch(100, "UCL", "CL", "QUARTER_FINALS", teamBayern, teamArsenal, "Bayern", "Arsenal")
+	firstLeg.Status = "FINISHED"
+	firstLeg.Score.FullTime.Home, firstLeg.Score.FullTime.Away = 1, 1
+
+	c := &fakeCalculator{
+		leagues: map[int]string{
+			teamRealMadrid: "LaLiga", teamBarcelona: "LaLiga", teamGetafe: "LaLiga", teamCelta: "LaLiga",
+			teamLuton: "PremierLeague", teamBurnley: "PremierLeague", teamArsenal: "PremierLeague",
+			teamBayern: "Bundesliga",
+		},
+		standings: map[string][]types.Standing{
+			"LaLiga":        fakeTable("LaLiga", 30, map[int]int{1: teamRealMadrid, 2: teamBarcelona, 10: teamGetafe, 11: teamCelta}),
+			"PremierLeague": fakeTable("PremierLeague", 34, map[int]int{2: teamArsenal, 17: teamBurnley, 18: teamLuton}),
+			"Bundesliga":    fakeTable("Bundesliga", 28, map[int]int{1: teamBayern}),
+		},
+		recent: map[int][]types.Match{
+			teamRealMadrid: fakeForm(teamRealMadrid, "WWWDW"),
+			teamBarcelona:  fakeForm(teamBarcelona, "WWLWW"),
+			teamGetafe:     fakeForm(teamGetafe, "DLDWD"),
+			teamCelta:      fakeForm(teamCelta, "LDWDL"),
+			teamLuton:      fakeForm(teamLuton, "LLDLW"),
+			teamBurnley:    fakeForm(teamBurnley, "LDLLD"),
+			teamArsenal:    fakeForm(teamArsenal, "WWDWW"),
+			teamBayern:     fakeForm(teamBayern, "WWWWL"),
+		},
+		firstLegs: map[int]*types.Match{fixtureCLTie: &firstLeg},
+		rivalries: map[[2]int]float64{{teamBarcelona, teamRealMadrid}: 0.35},
+		elo: map[int]float64{
+			teamRealMadrid: 1850, teamBarcelona: 1820, teamGetafe: 1520, teamCelta: 1510,
+			teamLuton: 1400, teamBurnley: 1410, teamArsenal: 1830, teamBayern: 1860,
+		},
+		predictions: map[int]*types.Prediction{
+			fixtureClasico:    {HomeWin: 0.42, Draw: 0.27, AwayWin: 0.31, Closeness: 0.89},
+			fixtureMidTable:   {HomeWin: 0.38, Draw: 0.32, AwayWin: 0.30, Closeness: 0.92},
+			fixtureSixPointer: {HomeWin: 0.40, Draw: 0.30, AwayWin: 0.30, Closeness: 0.90},
+			fixtureCLTie:      {HomeWin: 0.40, Draw: 0.27, AwayWin: 0.33, Closeness: 0.93},
+		},
+	}
+	return c
+}
+
+// Фикстура и допустимый диапазон рейтинга для каждой стратегии
+type calibrationFixture struct {
+	name   string
+	match  types.Match
+	ranges map[string][2]float64
+}
+
+func calibrationFixtures() []calibrationFixture {
+	return []calibrationFixture{
+		{
+			name:   "clasico",
+			match:  fixtureMatch(fixtureClasico, "LaLiga", "PD", regularSeasonStage, teamRealMadrid, teamBarcelona, "Real Madrid", "Barcelona"),
+			ranges: map[string][2]float64{"heuristic": {0.8, 1.0}, "balance": {0.85, 1.0}, "elo": {0.8, 1.0}},
+		},
+		{
+			name:   "mid_table",
+			match:  fixtureMatch(fixtureMidTable, "LaLiga", "PD", regularSeasonStage, teamGetafe, teamCelta, "Getafe", "Celta"),
+			ranges: map[string][2]float64{"heuristic": {0.3, 0.5}, "balance": {0.5, 0.7}, "elo": {0.5, 0.7}},
+		},
+		{
+			name:   "relegation_six_pointer",
+			match:  fixtureMatch(fixtureSixPointer, "EPL", "PL", regularSeasonStage, teamLuton, teamBurnley, "Luton", "Burnley"),
+			ranges: map[string][2]float64{"heuristic": {0.45, 0.65}, "balance": {0.55, 0.75}, "elo": {0.65, 0.85}},
+		},
+		{
+			name:   "cross_league_cl_tie",
+			match:  fixtureMatch(fixtureCLTie, "UCL", "CL", "QUARTER_FINALS", teamArsenal, teamBayern, "Arsenal", "Bayern"),
+			ranges: map[string][2]float64{"heuristic": {0.9, 1.0}, "balance": {0.75, 0.95}, "elo": {0.75, 0.95}},
+		},
+	}
+}
+
+func calibrationStrategies(t *testing.T) []RatingStrategy {
+	profiles, err := NewRatingProfiles("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []RatingStrategy{NewHeuristicStrategy(profiles), NewBalanceStrategy(profiles), NewEloStrategy(profiles)}
+}
+
+// Рейтинги всех фикстур всеми стратегиями: стратегия -> фикстура -> рейтинг
+func calibrationRatings(t *testing.T) map[string]map[string]float64 {
+	ctx := context.Background()
+	calculator := calibrationWorld()
+	ratings := make(map[string]map[string]float64)
+	for _, strategy := range calibrationStrategies(t) {
+		service := NewMatchesService(nil, nil, nil, strategy)
+		ratings[strategy.Name()] = make(map[string]float64)
+		for _, f := range calibrationFixtures() {
+			b, err := service.CalculateRatingOfMatch(ctx, f.match, calculator)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", strategy.Name(), f.name, err)
+			}
+			if b.Strategy != strategy.Name() || b.StrategyVersion != strategy.Version() {
+				t.Errorf("%s/%s: breakdown is not stamped with the strategy: %s v%s", strategy.Name(), f.name, b.Strategy, b.StrategyVersion)
+			}
+			ratings[strategy.Name()][f.name] = math.Round(b.Rating*10000) / 10000
+		}
+	}
+	return ratings
+}
+
+func TestRatingCalibration(t *testing.T) {
+	ratings := calibrationRatings(t)
+
+	for _, f := range calibrationFixtures() {
+		for strategy, bounds := range f.ranges {
+			if r := ratings[strategy][f.name]; r < bounds[0] || r > bounds[1] {
+				t.Errorf("%s/%s: rating %.4f outside [%.2f, %.2f]", strategy, f.name, r, bounds[0], bounds[1])
+			}
+		}
+	}
+
+	// Инварианты порядка, которые должны выполняться при любых весах
+	for strategy, r := range ratings {
+		if r["clasico"] <= r["mid_table"] {
+			t.Errorf("%s: El Clásico (%.4f) should outrank a mid-table game (%.4f)", strategy, r["clasico"], r["mid_table"])
+		}
+		if r["cross_league_cl_tie"] <= r["mid_table"] {
+			t.Errorf("%s: a CL quarter-final (%.4f) should outrank a mid-table game (%.4f)", strategy, r["cross_league_cl_tie"], r["mid_table"])
+		}
+		if r["relegation_six_pointer"] <= r["mid_table"] {
+			t.Errorf("%s: a relegation six-pointer (%.4f) should outrank a mid-table game (%.4f)", strategy, r["relegation_six_pointer"], r["mid_table"])
+		}
+	}
+
+	data, err := json.MarshalIndent(ratings, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = append(data, '\n')
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(calibrationGolden), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(calibrationGolden, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	golden, err := os.ReadFile(calibrationGolden)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	var want map[string]map[string]float64
+	if err := json.Unmarshal(golden, &want); err != nil {
+		t.Fatal(err)
+	}
+	for strategy, fixtures := range want {
+		for name, rating := range fixtures {
+			if got := ratings[strategy][name]; got != rating {
+				t.Errorf("%s/%s: rating changed from %.4f to %.4f; if intended, run with -update", strategy, name, rating, got)
+			}
+		}
+	}
+}
+
+func TestCalculatePositionOfTeams(t *testing.T) {
+	ctx := context.Background()
+	c := calibrationWorld()
+	clasico := fixtureMatch(fixtureClasico, "LaLiga", "PD", regularSeasonStage, teamRealMadrid, teamBarcelona, "Real Madrid", "Barcelona")
+
+	home, away, err := CalculatePositionOfTeams(ctx, c, clasico)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if home != 1 || math.Abs(away-18.0/19.0) > 1e-9 {
+		t.Errorf("leader and runner-up strengths = %.4f, %.4f", home, away)
+	}
+
+	unknown := fixtureMatch(5, "LaLiga", "PD", regularSeasonStage, teamRealMadrid, 4242, "Real Madrid", "Unknown")
+	if _, _, err := CalculatePositionOfTeams(ctx, c, unknown); err == nil {
+		t.Error("a team without a league should be an error")
+	}
+
+	// Команда есть в лиге, но не в таблице: средняя сила
+	c.leagues[4242] = "LaLiga"
+	if _, away, err := CalculatePositionOfTeams(ctx, c, unknown); err != nil || away != neutralStrength {
+		t.Errorf("a team missing from the table should be neutral, got %.2f, %v", away, err)
+	}
+}
+
+// Матч лиги с командой без лиги не получает рейтинг, а пропускается
+func TestRatingSkipsUnknownLeagues(t *testing.T) {
+	ctx := context.Background()
+	c := calibrationWorld()
+	c.leagues[4242] = ""
+	unknown := fixtureMatch(5, "LaLiga", "PD", regularSeasonStage, teamRealMadrid, 4242, "Real Madrid", "Unknown")
+	profiles, err := NewRatingProfiles("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Стратегия на Эло оценивает такие матчи без веса лиги
+	for _, strategy := range []RatingStrategy{NewHeuristicStrategy(profiles), NewBalanceStrategy(profiles)} {
+		if b, err := strategy.Rate(ctx, unknown, c); !errors.Is(err, ErrUnknownLeagues) {
+			t.Errorf("%s: expected ErrUnknownLeagues, got %+v, %v", strategy.Name(), b, err)
+		}
+	}
+}
+
+func TestGetDerbyBonus(t *testing.T) {
+	ctx := context.Background()
+	c := calibrationWorld()
+	clasico := fixtureMatch(fixtureClasico, "LaLiga", "PD", regularSeasonStage, teamRealMadrid, teamBarcelona, "Real Madrid", "Barcelona")
+	reversed := fixtureMatch(fixtureClasico, "LaLiga", "PD", regularSeasonStage, teamBarcelona, teamRealMadrid, "Barcelona", "Real Madrid")
+	midTable := fixtureMatch(fixtureMidTable, "LaLiga", "PD", regularSeasonStage, teamGetafe, teamCelta, "Getafe", "Celta")
+
+	if bonus := GetDerbyBonus(ctx, c, clasico); bonus != 0.35 {
+		t.Errorf("El Clásico bonus = %.2f, want 0.35", bonus)
+	}
+	if bonus := GetDerbyBonus(ctx, c, reversed); bonus != 0.35 {
+		t.Errorf("the bonus should not depend on who plays at home, got %.2f", bonus)
+	}
+	if bonus := GetDerbyBonus(ctx, c, midTable); bonus != 0 {
+		t.Errorf("a non-derby should get no bonus, got %.2f", bonus)
+	}
+}
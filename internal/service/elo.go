@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	mongoRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Параметры модели Эло
+const (
+	// Стартовый рейтинг команды без истории
+	EloInitial = 1500.0
+	// Коэффициент K: насколько сильно один матч меняет рейтинг
+	eloK = 20.0
+	// Преимущество своего поля в очках Эло
+	eloHomeAdvantage = 60.0
+	// За сколько дней до последнего учтённого матча результаты пересматриваются при каждом запуске:
+	// статус FINISHED может прийти позже, чем закончатся матчи, начавшиеся после
+	eloRescanDays = 7
+)
+
+// EloExpected возвращает ожидаемый результат хозяев (от 0 до 1) с учётом преимущества своего поля
+func EloExpected(homeRating, awayRating float64) float64 {
+	return 1 / (1 + math.Pow(10, (awayRating-homeRating-eloHomeAdvantage)/400))
+}
+
+// EloGoalMultiplier увеличивает изменение рейтинга за крупные победы
+// Формула из World Football Elo Ratings
+func EloGoalMultiplier(goalDiff int) float64 {
+	if goalDiff < 0 {
+		goalDiff = -goalDiff
+	}
+	switch {
+	case goalDiff <= 1:
+		return 1
+	case goalDiff == 2:
+		return 1.5
+	default:
+		return (11 + float64(goalDiff)) / 8
+	}
+}
+
+// EloDelta возвращает изменение рейтинга хозяев по итогу матча; у гостей оно с обратным знаком
+func EloDelta(homeRating, awayRating float64, homeGoals, awayGoals int) float64 {
+	actual := 0.5
+	if homeGoals > awayGoals {
+		actual = 1
+	} else if homeGoals < awayGoals {
+		actual = 0
+	}
+	return eloK * EloGoalMultiplier(homeGoals-awayGoals) * (actual - EloExpected(homeRating, awayRating))
+}
+
+// EloService ведёт рейтинги Эло команд по сыгранным матчам из коллекции matches
+type EloService struct {
+	eloStore     mongoRepo.EloStore
+	matchesStore mongoRepo.FinishedMatchesStore
+}
+
+// Конструктор для создания нового экземпляра EloService
+func NewEloService(eloStore mongoRepo.EloStore, matchesStore mongoRepo.FinishedMatchesStore) *EloService {
+	return &EloService{
+		eloStore:     eloStore,
+		matchesStore: matchesStore,
+	}
+}
+
+// Метод для инкрементального обновления рейтингов
+// Учитывает завершённые матчи, которые ещё не были учтены, в том числе опоздавшие
+// в пределах eloRescanDays до последнего учтённого матча
+// Возвращает количество учтённых матчей
+func (s *EloService) HandleUpdate(ctx context.Context) (int, error) {
+	state, err := s.eloStore.GetEloState(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error getting elo state: %w", err)
+	}
+	return s.replay(ctx, state)
+}
+
+// Метод для полного пересчёта рейтингов с нуля по всей истории матчей
+func (s *EloService) HandleRebuild(ctx context.Context) (int, error) {
+	if err := s.eloStore.ResetElo(ctx); err != nil {
+		return 0, fmt.Errorf("error resetting elo: %w", err)
+	}
+	return s.replay(ctx, types.EloState{})
+}
+
+// Прогоняет не учтённые в state матчи в хронологическом порядке и сохраняет изменённые команды
+func (s *EloService) replay(ctx context.Context, state types.EloState) (int, error) {
+	finished, err := s.matchesStore.GetFinishedMatches(ctx, eloRescanStart(state.LastMatchDate))
+	if err != nil {
+		return 0, fmt.Errorf("error getting finished matches: %w", err)
+	}
+	matches := newFinishedMatches(finished, state)
+	if len(matches) == 0 {
+		return 0, nil
+	}
+
+	elos, err := s.eloStore.GetTeamElos(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error getting team elos: %w", err)
+	}
+	byTeam := make(map[int]*types.TeamElo, len(elos))
+	for i := range elos {
+		byTeam[elos[i].TeamID] = &elos[i]
+	}
+	team := func(id int, name string) *types.TeamElo {
+		t, ok := byTeam[id]
+		if !ok {
+			t = &types.TeamElo{TeamID: id, TeamName: name, Rating: EloInitial}
+			byTeam[id] = t
+		}
+		return t
+	}
+
+	changed := make(map[int]*types.TeamElo)
+	for _, m := range matches {
+		home := team(m.HomeTeam.ID, m.HomeTeam.Name)
+		away := team(m.AwayTeam.ID, m.AwayTeam.Name)
+		delta := EloDelta(home.Rating, away.Rating, m.Score.FullTime.Home, m.Score.FullTime.Away)
+		applyElo(home, m, delta)
+		applyElo(away, m, -delta)
+		changed[home.TeamID] = home
+		changed[away.TeamID] = away
+	}
+
+	updated := make([]types.TeamElo, 0, len(changed))
+	for _, t := range changed {
+		updated = append(updated, *t)
+	}
+	if err := s.eloStore.SaveTeamElos(ctx, updated); err != nil {
+		return 0, fmt.Errorf("error saving team elos: %w", err)
+	}
+
+	state = nextEloState(finished, state, matches[len(matches)-1])
+	state.UpdatedAt = time.Now().UTC()
+	if err := s.eloStore.SaveEloState(ctx, state); err != nil {
+		return 0, fmt.Errorf("error saving elo state: %w", err)
+	}
+	logrus.Infof("Elo updated with %d matches for %d teams", len(matches), len(updated))
+	return len(matches), nil
+}
+
+// Начало пересматриваемого окна: за eloRescanDays до последнего учтённого матча
+// Пустая строка, если матчи ещё не учитывались
+func eloRescanStart(lastMatchDate string) string {
+	last, err := time.Parse(time.RFC3339, lastMatchDate)
+	if err != nil {
+		return ""
+	}
+	return last.AddDate(0, 0, -eloRescanDays).UTC().Format(time.RFC3339)
+}
+
+// Убирает дубликаты и уже учтённые матчи и сортирует по (UTCDate, ID)
+func newFinishedMatches(matches []types.Match, state types.EloState) []types.Match {
+	processed := make(map[int]bool, len(state.ProcessedIDs))
+	for _, id := range state.ProcessedIDs {
+		processed[id] = true
+	}
+	seen := make(map[int]bool, len(matches))
+	var result []types.Match
+	for _, m := range matches {
+		if seen[m.ID] || m.Status != "FINISHED" {
+			continue
+		}
+		seen[m.ID] = true
+		if processed[m.ID] || m.UTCDate < state.WindowStart {
+			continue
+		}
+		// Состояние без окна помнит только последний учтённый матч
+		if state.WindowStart == "" && (m.UTCDate < state.LastMatchDate || (m.UTCDate == state.LastMatchDate && m.ID <= state.LastMatchID)) {
+			continue
+		}
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].UTCDate != result[j].UTCDate {
+			return result[i].UTCDate < result[j].UTCDate
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Состояние после учёта матчей: finished - все сыгранные матчи пересмотренного окна,
+// last - самый поздний из только что учтённых
+// Все матчи из finished к этому моменту учтены, поэтому окно заполняется ими целиком
+func nextEloState(finished []types.Match, state types.EloState, last types.Match) types.EloState {
+	next := types.EloState{LastMatchDate: state.LastMatchDate, LastMatchID: state.LastMatchID}
+	if last.UTCDate > next.LastMatchDate || (last.UTCDate == next.LastMatchDate && last.ID > next.LastMatchID) {
+		next.LastMatchDate, next.LastMatchID = last.UTCDate, last.ID
+	}
+	next.WindowStart = eloRescanStart(next.LastMatchDate)
+	seen := make(map[int]bool)
+	for _, m := range finished {
+		if m.Status == "FINISHED" && m.UTCDate >= next.WindowStart && !seen[m.ID] {
+			seen[m.ID] = true
+			next.ProcessedIDs = append(next.ProcessedIDs, m.ID)
+		}
+	}
+	return next
+}
+
+// Применяет изменение рейтинга к команде и добавляет точку в историю
+func applyElo(t *types.TeamElo, m types.Match, delta float64) {
+	t.Rating += delta
+	t.Matches++
+	t.History = append(t.History, types.EloPoint{MatchID: m.ID, Date: m.UTCDate, Rating: t.Rating, Delta: delta})
+}
+
+// Метод для получения команд с самым высоким рейтингом
+func (s *EloService) HandleGetTop(ctx context.Context, limit int) ([]types.TeamElo, error) {
+	elos, err := s.eloStore.GetTeamElos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(elos, func(i, j int) bool {
+		return elos[i].Rating > elos[j].Rating
+	})
+	if len(elos) > limit {
+		elos = elos[:limit]
+	}
+	return elos, nil
+}
+
+// Метод для поиска команды по части названия (без учёта регистра)
+// Возвращает nil, если ничего не найдено
+func (s *EloService) HandleFindTeam(ctx context.Context, query string) (*types.TeamElo, error) {
+	elos, err := s.eloStore.GetTeamElos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	var best *types.TeamElo
+	for i := range elos {
+		name := strings.ToLower(elos[i].TeamName)
+		if name == query {
+			return &elos[i], nil
+		}
+		if strings.Contains(name, query) && (best == nil || elos[i].Matches > best.Matches) {
+			best = &elos[i]
+		}
+	}
+	return best, nil
+}
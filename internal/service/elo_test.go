@@ -0,0 +1,95 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Сыгранный матч АПЛ; общий конструктор для тестов пакета
+func playedMatch(id int, date string, home, away, hg, ag int) types.Match {
+	var m types.Match
+	m.ID = id
+	m.UTCDate = date
+	m.Status = "FINISHED"
+	m.Competition.Name = "EPL"
+	m.Competition.Code = "PL"
+	m.Stage = "REGULAR_SEASON"
+	m.HomeTeam.ID = home
+	m.AwayTeam.ID = away
+	m.Score.FullTime.Home = hg
+	m.Score.FullTime.Away = ag
+	return m
+}
+
+func TestEloExpectedHomeAdvantage(t *testing.T) {
+	if e := EloExpected(1500, 1500); e <= 0.5 {
+		t.Errorf("equal teams at home: expected > 0.5, got %.3f", e)
+	}
+	if e := EloExpected(1500-eloHomeAdvantage, 1500); math.Abs(e-0.5) > 1e-9 {
+		t.Errorf("home advantage should be offset exactly, got %.3f", e)
+	}
+}
+
+func TestEloDelta(t *testing.T) {
+	win := EloDelta(1500, 1500, 1, 0)
+	bigWin := EloDelta(1500, 1500, 4, 0)
+	draw := EloDelta(1500, 1500, 1, 1)
+	loss := EloDelta(1500, 1500, 0, 1)
+
+	if win <= 0 || loss >= 0 {
+		t.Errorf("win should add and loss should subtract rating: win %.2f, loss %.2f", win, loss)
+	}
+	if bigWin <= win {
+		t.Errorf("bigger margin should change rating more: 4-0 %.2f, 1-0 %.2f", bigWin, win)
+	}
+	if draw >= 0 {
+		t.Errorf("home draw between equal teams should cost rating, got %.2f", draw)
+	}
+}
+
+func TestNewFinishedMatchesOrderAndDedup(t *testing.T) {
+	scheduled := playedMatch(4, "2025-05-03T18:00:00Z", 1, 2, 0, 0)
+	scheduled.Status = "SCHEDULED"
+	matches := []types.Match{
+		playedMatch(3, "2025-05-02T18:00:00Z", 1, 2, 1, 0),
+		playedMatch(2, "2025-05-01T18:00:00Z", 1, 2, 1, 0),
+		playedMatch(1, "2025-05-01T18:00:00Z", 1, 2, 1, 0),
+		playedMatch(3, "2025-05-02T18:00:00Z", 1, 2, 1, 0),
+		scheduled,
+	}
+	state := types.EloState{LastMatchDate: "2025-05-01T18:00:00Z", LastMatchID: 1}
+
+	got := newFinishedMatches(matches, state)
+	if len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 {
+		t.Errorf("unexpected matches: %+v", got)
+	}
+}
+
+func TestEloWindowCountsLateResults(t *testing.T) {
+	match := func(id int, date string) types.Match {
+		return playedMatch(id, date, 1, 2, 1, 0)
+	}
+	// Первый запуск: матч 2 ещё не получил статус FINISHED
+	first := []types.Match{match(1, "2025-05-01T15:00:00Z"), match(3, "2025-05-02T18:00:00Z")}
+	counted := newFinishedMatches(first, types.EloState{})
+	state := nextEloState(first, types.EloState{}, counted[len(counted)-1])
+	if state.LastMatchDate != "2025-05-02T18:00:00Z" || state.WindowStart != "2025-04-25T18:00:00Z" || len(state.ProcessedIDs) != 2 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	// Второй запуск: результат матча 2 пришёл после того, как учтён более поздний матч 3
+	second := []types.Match{match(1, "2025-05-01T15:00:00Z"), match(2, "2025-05-01T20:00:00Z"), match(3, "2025-05-02T18:00:00Z")}
+	got := newFinishedMatches(second, state)
+	if len(got) != 1 || got[0].ID != 2 {
+		t.Fatalf("late match should be counted once, got %+v", got)
+	}
+	state = nextEloState(second, state, got[0])
+	if state.LastMatchDate != "2025-05-02T18:00:00Z" || len(state.ProcessedIDs) != 3 {
+		t.Errorf("unexpected state %+v", state)
+	}
+	if again := newFinishedMatches(second, state); len(again) != 0 {
+		t.Errorf("nothing new should be counted, got %+v", again)
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"math"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+const (
+	// По скольким последним матчам считается зрелищность команды
+	EntertainmentMatches = 20
+	// Зрелищность команды без истории
+	entertainmentNeutral = 0.5
+	// Сколько «средних» матчей добавляется к истории, чтобы пара игр не давала крайних значений
+	entertainmentPriorMatches = 5.0
+	// Голов за матч, при которых голевая часть индекса максимальна
+	entertainmentMaxGoals = 4.0
+)
+
+// Веса частей индекса зрелищности
+const (
+	entertainmentGoalsWeight = 0.5
+	entertainmentBothWeight  = 0.3
+	entertainmentLateWeight  = 0.2
+)
+
+// TeamEntertainment считает зрелищность команды по её сыгранным матчам
+// Голы и «обе забьют» считаются по всем матчам, поздние развязки - только по матчам со счётом первого тайма;
+// если таких нет, индекс складывается из первых двух частей
+func TeamEntertainment(teamID int, matches []types.Match) types.Entertainment {
+	e := types.Entertainment{TeamID: teamID}
+	var goals, bothScored, late int
+	for _, m := range matches {
+		if m.Status != "FINISHED" {
+			continue
+		}
+		ft := m.Score.FullTime
+		e.Matches++
+		goals += ft.Home + ft.Away
+		if ft.Home > 0 && ft.Away > 0 {
+			bothScored++
+		}
+		if m.Score.HalfTime != nil {
+			e.LateSwingMatches++
+			if lateSwing(m) {
+				late++
+			}
+		}
+	}
+	if e.Matches == 0 {
+		e.Index = entertainmentNeutral
+		return e
+	}
+	e.GoalsPerGame = float64(goals) / float64(e.Matches)
+	e.BothScoredRate = float64(bothScored) / float64(e.Matches)
+
+	raw := entertainmentGoalsWeight*math.Min(e.GoalsPerGame/entertainmentMaxGoals, 1) + entertainmentBothWeight*e.BothScoredRate
+	weight := entertainmentGoalsWeight + entertainmentBothWeight
+	if e.LateSwingMatches > 0 {
+		e.LateSwingRate = float64(late) / float64(e.LateSwingMatches)
+		raw += entertainmentLateWeight * e.LateSwingRate
+		weight += entertainmentLateWeight
+	}
+	n := float64(e.Matches)
+	e.Index = (n*raw/weight + entertainmentPriorMatches*entertainmentNeutral) / (n + entertainmentPriorMatches)
+	return e
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+func TestTeamEntertainment(t *testing.T) {
+	var wild, dull []types.Match
+	for i := 0; i < 10; i++ {
+		m := playedMatch(i+1, "2025-03-01T15:00:00Z", 1, 2, 3, 2)
+		m.Score.HalfTime = &types.HalfTimeScore{Home: 0, Away: 1}
+		wild = append(wild, m)
+		dull = append(dull, playedMatch(i+11, "2025-03-01T15:00:00Z", 3, 4, 1, 0))
+	}
+
+	e := TeamEntertainment(1, wild)
+	if e.Matches != 10 || e.GoalsPerGame != 5 || e.BothScoredRate != 1 || e.LateSwingRate != 1 {
+		t.Errorf("unexpected stats %+v", e)
+	}
+	if e.Index <= entertainmentNeutral {
+		t.Errorf("high-scoring comebacks should be above average, got %.2f", e.Index)
+	}
+
+	d := TeamEntertainment(3, dull)
+	if d.LateSwingMatches != 0 || d.LateSwingRate != 0 {
+		t.Errorf("matches without half-time scores should not count for late swings, got %+v", d)
+	}
+	if d.Index >= entertainmentNeutral {
+		t.Errorf("narrow low-scoring wins should be below average, got %.2f", d.Index)
+	}
+
+	if empty := TeamEntertainment(5, nil); empty.Index != entertainmentNeutral {
+		t.Errorf("a team without matches should be neutral, got %.2f", empty.Index)
+	}
+	// Одна голевая игра не делает команду самой зрелищной
+	if one := TeamEntertainment(1, wild[:1]); one.Index >= e.Index {
+		t.Errorf("a short history should be pulled towards neutral: %.2f >= %.2f", one.Index, e.Index)
+	}
+}
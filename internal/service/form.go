@@ -0,0 +1,90 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+const (
+	// Сколько последних матчей берётся для оценки формы
+	FormMatches = 5
+	// Во сколько раз каждый следующий (более старый) матч весит меньше предыдущего
+	formRecencyDecay = 0.75
+	// Доли очков и разницы мячей в оценке одного матча
+	formPointsWeight   = 0.8
+	formGoalDiffWeight = 0.2
+	// Разница мячей, которая считается максимальной при нормализации
+	formMaxGoalDiff = 3
+	// Оценка формы команды без сыгранных матчей
+	neutralForm = 0.5
+)
+
+// AnalyzeForm вычисляет форму команды по последним матчам
+// Учитываются только завершённые матчи, от самого свежего к более старым:
+// очки с учётом ничьих и разница мячей, свежие матчи весят больше
+func AnalyzeForm(matches []types.Match, teamID int) types.Form {
+	finished := make([]types.Match, 0, len(matches))
+	seen := make(map[int]bool, len(matches))
+	for _, m := range matches {
+		if seen[m.ID] || m.Status != "FINISHED" || (m.HomeTeam.ID != teamID && m.AwayTeam.ID != teamID) {
+			continue
+		}
+		seen[m.ID] = true
+		finished = append(finished, m)
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].UTCDate > finished[j].UTCDate
+	})
+	if len(finished) > FormMatches {
+		finished = finished[:FormMatches]
+	}
+
+	form := types.Form{Played: len(finished), Rating: neutralForm}
+	if len(finished) == 0 {
+		return form
+	}
+
+	var points, goalDiff int
+	var score, weights float64
+	lastFive := make([]byte, len(finished))
+	weight := 1.0
+	for i, m := range finished {
+		scored, conceded := m.Score.FullTime.Home, m.Score.FullTime.Away
+		if m.AwayTeam.ID == teamID {
+			scored, conceded = conceded, scored
+		}
+		diff := scored - conceded
+
+		var matchPoints int
+		switch {
+		case diff > 0:
+			matchPoints = 3
+			lastFive[len(finished)-1-i] = 'W'
+		case diff == 0:
+			matchPoints = 1
+			lastFive[len(finished)-1-i] = 'D'
+		default:
+			lastFive[len(finished)-1-i] = 'L'
+		}
+		points += matchPoints
+		goalDiff += diff
+
+		clamped := float64(max(-formMaxGoalDiff, min(formMaxGoalDiff, diff)))
+		matchScore := formPointsWeight*float64(matchPoints)/3 + formGoalDiffWeight*(clamped+formMaxGoalDiff)/(2*formMaxGoalDiff)
+		score += weight * matchScore
+		weights += weight
+		weight *= formRecencyDecay
+	}
+
+	form.PointsPerGame = float64(points) / float64(len(finished))
+	form.GoalDiffPerGame = float64(goalDiff) / float64(len(finished))
+	form.Rating = score / weights
+	form.LastFive = string(lastFive)
+	return form
+}
+
+// CalculateForm вычисляет оценку формы команды от 0 до 1 на основе последних матчей
+func CalculateForm(matches []types.Match, teamID int) float64 {
+	return AnalyzeForm(matches, teamID).Rating
+}
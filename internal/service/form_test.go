@@ -0,0 +1,56 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+func TestAnalyzeForm(t *testing.T) {
+	const team = 1
+	scheduled := playedMatch(6, "2025-05-06T18:00:00Z", team, 2, 0, 0)
+	scheduled.Status = "SCHEDULED"
+	matches := []types.Match{
+		playedMatch(3, "2025-05-03T18:00:00Z", 4, team, 2, 2), // ничья в гостях
+		playedMatch(1, "2025-05-01T18:00:00Z", team, 2, 3, 0), // победа дома
+		playedMatch(2, "2025-05-02T18:00:00Z", team, 3, 0, 1), // поражение дома
+		playedMatch(4, "2025-05-04T18:00:00Z", 5, team, 0, 2), // победа в гостях
+		scheduled,
+	}
+
+	form := AnalyzeForm(matches, team)
+	if form.Played != 4 {
+		t.Errorf("Played = %d, want 4", form.Played)
+	}
+	if form.LastFive != "WLDW" {
+		t.Errorf("LastFive = %q, want %q", form.LastFive, "WLDW")
+	}
+	if math.Abs(form.PointsPerGame-7.0/4) > 1e-9 {
+		t.Errorf("PointsPerGame = %.3f, want 1.75", form.PointsPerGame)
+	}
+	if math.Abs(form.GoalDiffPerGame-1) > 1e-9 {
+		t.Errorf("GoalDiffPerGame = %.3f, want 1", form.GoalDiffPerGame)
+	}
+	if form.Rating <= 0 || form.Rating >= 1 {
+		t.Errorf("Rating = %.3f, want in (0, 1)", form.Rating)
+	}
+}
+
+func TestAnalyzeFormRecency(t *testing.T) {
+	const team = 1
+	recentWin := []types.Match{
+		playedMatch(1, "2025-05-01T18:00:00Z", team, 2, 0, 1),
+		playedMatch(2, "2025-05-02T18:00:00Z", team, 2, 1, 0),
+	}
+	recentLoss := []types.Match{
+		playedMatch(1, "2025-05-01T18:00:00Z", team, 2, 1, 0),
+		playedMatch(2, "2025-05-02T18:00:00Z", team, 2, 0, 1),
+	}
+	if AnalyzeForm(recentWin, team).Rating <= AnalyzeForm(recentLoss, team).Rating {
+		t.Error("a recent win should weigh more than an older one")
+	}
+	if got := AnalyzeForm(nil, team).Rating; got != neutralForm {
+		t.Errorf("empty form = %.2f, want neutral %.2f", got, neutralForm)
+	}
+}
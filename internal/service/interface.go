@@ -10,6 +10,16 @@ import (
 type Calculator interface {
 	HandleGetLeague(ctx context.Context, collectionName string, teamID int) (string, error)
 	HandleGetTeamStanding(ctx context.Context, leagueKey string, teamID int) (int, error)
+	HandleGetStandings(ctx context.Context, leagueKey string) ([]types.Standing, error)
 	HandleGetRecentMatches(ctx context.Context, teamID int, lastN int) ([]types.Match, error)
-	HandleGetTeamShortName(ctx context.Context, leagueKey string, teamName string) (string, error)
+	HandleGetFirstLeg(ctx context.Context, match types.Match) (*types.Match, error)
+	HandleGetRivalryIntensity(ctx context.Context, homeTeamID, awayTeamID int) (float64, error)
+	HandleGetTeamElo(ctx context.Context, teamID int) (float64, error)
+	// Прогноз по модели Пуассона; nil, если данных для прогноза нет
+	HandleGetPrediction(ctx context.Context, match types.Match) (*types.Prediction, error)
+}
+
+// Predictor строит прогноз матча; реализуется StatsService
+type Predictor interface {
+	HandleGetPrediction(ctx context.Context, match types.Match) (*types.Prediction, error)
 }
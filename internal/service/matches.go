@@ -3,24 +3,46 @@ package service
 import (
 	"context"
 	"fmt"
-	"log"
+	"sort"
+	"time"
 
 	"github.com/vsespontanno/tgbot_fschedule/internal/client"
 	db "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
 	"github.com/vsespontanno/tgbot_fschedule/internal/types"
 )
 
+// Причины пересчёта рейтинга, которые записываются в историю
+const (
+	// Плановый расчёт при обновлении расписания
+	RatingReasonSchedule = "schedule"
+	// Изменилось место или очки команды в таблице
+	RatingReasonStandings = "standings"
+	// Команда сыграла матч, и у неё изменилась форма
+	RatingReasonForm = "form"
+)
+
+// На сколько дней вперёд пересчитываются матчи команд при изменении таблиц или формы
+const ratingRecomputeDays = 14
+
 // MatchesService предоставляет методы для работы с матчами
 type MatchesService struct {
 	matchesStore db.MatchesStore
+	historyStore db.RatingHistoryStore
 	apiClient    client.MatchApiClient
+	strategy     RatingStrategy
+	now          func() time.Time
 }
 
 // Конструктор для создания нового экземпляра MatchesService
-func NewMatchesService(matchesStore db.MatchesStore, apiClient client.MatchApiClient) *MatchesService {
+// strategy - стратегия расчёта рейтинга матчей; топ-матчи строятся только по её рейтингам
+// historyStore - история рейтингов; каждое изменение рейтинга матча записывается туда
+func NewMatchesService(matchesStore db.MatchesStore, historyStore db.RatingHistoryStore, apiClient client.MatchApiClient, strategy RatingStrategy) *MatchesService {
 	return &MatchesService{
 		matchesStore: matchesStore,
+		historyStore: historyStore,
 		apiClient:    apiClient,
+		strategy:     strategy,
+		now:          time.Now,
 	}
 }
 
@@ -39,8 +61,8 @@ func (s *MatchesService) HandleReqMatches(ctx context.Context, from string, to s
 }
 
 // Метод для обновления рейтинга матча в базе MongoDB
-func (s *MatchesService) HandleSaveMatchRating(ctx context.Context, match types.Match, rating float64) error {
-	return s.matchesStore.UpdateMatchRatingInMongoDB(match, rating)
+func (s *MatchesService) HandleSaveMatchRating(ctx context.Context, match types.Match, breakdown *types.RatingBreakdown) error {
+	return s.matchesStore.UpdateMatchRatingInMongoDB(match, breakdown)
 }
 
 // Метод для обновления матчей в базе MongoDB, дабы при обновлении в фоне не было дубликатов
@@ -48,62 +70,208 @@ func (s *MatchesService) HandleUpsertMatch(ctx context.Context, match types.Matc
 	return s.matchesStore.UpsertMatch(ctx, match)
 }
 
-// Метод для расчёта рейтинга матча
-// Использует калькулятор для получения необходимых данных и расчёта рейтинга
-func (s *MatchesService) CalculateRatingOfMatch(ctx context.Context, match types.Match, calculator Calculator) (float64, error) {
-	// 1) Сила команд по позициям
-	homeStrength, awayStrength, err := CalculatePositionOfTeams(ctx, calculator, match)
+// Метод для расчёта рейтинга матча выбранной стратегией
+// Возвращает разбор рейтинга с названием и версией стратегии; итоговое число лежит в поле Rating
+func (s *MatchesService) CalculateRatingOfMatch(ctx context.Context, match types.Match, calculator Calculator) (*types.RatingBreakdown, error) {
+	breakdown, err := s.strategy.Rate(ctx, match, calculator)
 	if err != nil {
-		return 0, fmt.Errorf("error calculating team strengths: %s", err)
+		return nil, err
 	}
+	breakdown.Strategy = s.strategy.Name()
+	breakdown.StrategyVersion = s.strategy.Version()
+	return breakdown, nil
+}
 
-	// 2) Лиги и вес
-	homeLeague, awayLeague, err := GetLeaguesForTeams(ctx, calculator, match.HomeTeam.ID, match.AwayTeam.ID)
-	if err != nil || homeLeague == "" || awayLeague == "" {
-		fmt.Printf("Матч %s - %s пропущен: проблема с лигами\nЛиги: %s - %s\nАйдишники: %d - %d\n", match.HomeTeam.Name, match.AwayTeam.Name, homeLeague, awayLeague, match.HomeTeam.ID, match.AwayTeam.ID)
-		return 0, nil
+// Результат пакетного расчёта рейтинга одного матча
+type RatingResult struct {
+	Match     types.Match
+	Breakdown *types.RatingBreakdown
+	Err       error
+}
+
+// Метод для пакетного расчёта рейтинга списка матчей
+// Если калькулятор умеет загружать данные заранее, все данные для матчей берутся
+// несколькими запросами на весь список, а не несколькими запросами на каждый матч
+func (s *MatchesService) CalculateRatingsOfMatches(ctx context.Context, matches []types.Match, calculator Calculator) ([]RatingResult, error) {
+	if preloader, ok := calculator.(Preloader); ok {
+		preloaded, err := preloader.Preload(ctx, matches)
+		if err != nil {
+			return nil, fmt.Errorf("error preloading rating data: %w", err)
+		}
+		calculator = preloaded
+	}
+	results := make([]RatingResult, len(matches))
+	for i, match := range matches {
+		breakdown, err := s.CalculateRatingOfMatch(ctx, match, calculator)
+		results[i] = RatingResult{Match: match, Breakdown: breakdown, Err: err}
+	}
+	return results, nil
+}
+
+// Метод для сохранения посчитанных рейтингов вместе с матчами
+// Результаты с ошибкой пропускаются. Если рейтинг матча изменился по сравнению с последней
+// точкой истории, в историю добавляется новая точка с причиной reason
+// Возвращает число сохранённых матчей
+func (s *MatchesService) HandleSaveRatings(ctx context.Context, results []RatingResult, reason string) (int, error) {
+	var (
+		rated []types.Match
+		ids   []int
+	)
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		match := result.Match
+		breakdown := result.Breakdown
+		match.Rating = breakdown.Rating
+		match.RatingStrategy = breakdown.Strategy
+		match.RatingVersion = breakdown.StrategyVersion
+		match.RatingBreakdown = breakdown
+		if err := s.matchesStore.UpsertMatch(ctx, match); err != nil {
+			return len(rated), fmt.Errorf("error upserting match %d: %w", match.ID, err)
+		}
+		rated = append(rated, match)
+		ids = append(ids, match.ID)
 	}
-	avgLeagueWeight := (types.LeagueNorm[homeLeague] + types.LeagueNorm[awayLeague]) / 2.0
+	latest, err := s.historyStore.GetLatestRatingPoints(ctx, ids)
+	if err != nil {
+		return len(rated), fmt.Errorf("error getting rating history: %w", err)
+	}
+	points := newRatingPoints(rated, latest, reason, s.now().UTC())
+	if err := s.historyStore.AddRatingPoints(ctx, points); err != nil {
+		return len(rated), fmt.Errorf("error saving rating history: %w", err)
+	}
+	return len(rated), nil
+}
 
-	// 3) Форма команд
-	recentMatchesHome, err := calculator.HandleGetRecentMatches(ctx, match.HomeTeam.ID, 5)
+// Метод для сохранения итогов начавшихся и сыгранных матчей: статуса и счёта
+// Рейтинг таких матчей не меняется и остаётся предматчевым; не начавшиеся матчи пропускаются
+// Возвращает число сохранённых матчей
+func (s *MatchesService) HandleSaveResults(ctx context.Context, matches []types.Match) (int, error) {
+	saved := 0
+	for _, match := range matches {
+		if !match.Started() {
+			continue
+		}
+		if err := s.matchesStore.UpdateMatchResult(ctx, match); err != nil {
+			return saved, err
+		}
+		saved++
+	}
+	return saved, nil
+}
+
+// Метод для пересчёта рейтингов ближайших несыгранных матчей команд
+// Вызывается, когда у команд изменилось место в таблице или форма
+// Сохраняет только матчи, рейтинг которых изменился; возвращает их число
+func (s *MatchesService) HandleRecomputeForTeams(ctx context.Context, teamIDs []int, calculator Calculator, reason string) (int, error) {
+	if len(teamIDs) == 0 {
+		return 0, nil
+	}
+	now := s.now().UTC()
+	from := now.Format("2006-01-02")
+	to := now.AddDate(0, 0, ratingRecomputeDays).Format("2006-01-02")
+	all, err := s.matchesStore.GetMatchesInPeriod(ctx, "", from, to)
 	if err != nil {
-		log.Printf("Error getting recent matches for home team %d: %v", match.HomeTeam.ID, err)
+		return 0, fmt.Errorf("error getting upcoming matches: %w", err)
+	}
+	matches := upcomingTeamMatches(all, teamIDs, now)
+	if len(matches) == 0 {
+		return 0, nil
 	}
-	recentMatchesAway, err := calculator.HandleGetRecentMatches(ctx, match.AwayTeam.ID, 5)
+	results, err := s.CalculateRatingsOfMatches(ctx, matches, calculator)
 	if err != nil {
-		log.Printf("Error getting recent matches for away team %d: %v", match.AwayTeam.ID, err)
+		return 0, err
 	}
-	homeForm := CalculateForm(recentMatchesHome, match.HomeTeam.ID)
-	awayForm := CalculateForm(recentMatchesAway, match.AwayTeam.ID)
-	formFactor := (homeForm + awayForm) / 2.0
+	var changed []RatingResult
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		m := result.Match
+		if m.RatedBy() != result.Breakdown.Strategy || m.RatingVersion != result.Breakdown.StrategyVersion || m.Rating != result.Breakdown.Rating {
+			changed = append(changed, result)
+		}
+	}
+	return s.HandleSaveRatings(ctx, changed, reason)
+}
+
+// Метод для получения истории рейтинга матча, старые точки первыми
+func (s *MatchesService) HandleGetRatingHistory(ctx context.Context, matchID int) ([]types.RatingPoint, error) {
+	return s.historyStore.GetRatingHistory(ctx, matchID)
+}
 
-	// 4) Бонусы
-	derbyBonus := GetDerbyBonus(ctx, calculator, match)
-	stageBonus := 0.0
-	if homeLeague == "Champions League" && match.Stage != "" {
-		stageBonus = types.CLstage[match.Stage]
+// Несыгранные матчи указанных команд, которые начнутся после now
+func upcomingTeamMatches(matches []types.Match, teamIDs []int, now time.Time) []types.Match {
+	teams := make(map[int]bool, len(teamIDs))
+	for _, id := range teamIDs {
+		teams[id] = true
 	}
-	crossLeagueBonus := 0.0
-	if homeLeague != awayLeague {
-		crossLeagueBonus = 0.15 // Увеличен с 0.1 для большего эффекта
+	after := now.Format(time.RFC3339)
+	var upcoming []types.Match
+	for _, m := range matches {
+		if m.Started() || m.UTCDate < after {
+			continue
+		}
+		if teams[m.HomeTeam.ID] || teams[m.AwayTeam.ID] {
+			upcoming = append(upcoming, m)
+		}
 	}
+	return upcoming
+}
 
-	// 5) Финальный рейтинг
-	baseRating := (homeStrength+awayStrength)/2.0*0.15 + // Уменьшено влияние позиций
-		avgLeagueWeight*0.35 + // Снижено с 0.4
-		formFactor*0.15 // Добавлено влияние формы
-	rating := baseRating * (1 + derbyBonus + stageBonus + crossLeagueBonus)
+// Точки истории для матчей, рейтинг которых отличается от последней записанной точки
+func newRatingPoints(matches []types.Match, latest map[int]types.RatingPoint, reason string, at time.Time) []types.RatingPoint {
+	var points []types.RatingPoint
+	for _, m := range matches {
+		last, ok := latest[m.ID]
+		if ok && last.Rating == m.Rating && last.Strategy == m.RatingStrategy && last.StrategyVersion == m.RatingVersion {
+			continue
+		}
+		points = append(points, types.RatingPoint{
+			MatchID:         m.ID,
+			Rating:          m.Rating,
+			Strategy:        m.RatingStrategy,
+			StrategyVersion: m.RatingVersion,
+			Reason:          reason,
+			ComputedAt:      at,
+		})
+	}
+	return points
+}
 
-	// 6) Ограничение и минимальное значение
-	if rating > 1.0 {
-		rating = 1.0
+// UpcomingMatches возвращает не начавшиеся матчи списка: только их рейтинг имеет смысл считать
+func UpcomingMatches(matches []types.Match) []types.Match {
+	var upcoming []types.Match
+	for _, m := range matches {
+		if !m.Started() {
+			upcoming = append(upcoming, m)
+		}
 	}
-	if rating < 0.1 { // Минимальный рейтинг, чтобы избежать нулей
-		rating = 0.1
+	return upcoming
+}
+
+// FinishedTeams возвращает ID команд из сыгранных матчей списка: у них изменилась форма
+func FinishedTeams(matches []types.Match) []int {
+	seen := make(map[int]bool)
+	var teams []int
+	for _, m := range matches {
+		if m.Status != "FINISHED" {
+			continue
+		}
+		for _, id := range []int{m.HomeTeam.ID, m.AwayTeam.ID} {
+			if !seen[id] {
+				seen[id] = true
+				teams = append(teams, id)
+			}
+		}
 	}
+	return teams
+}
 
-	return rating, nil
+// Метод возвращает название стратегии, которой сервис считает рейтинг
+func (s *MatchesService) RatingStrategyName() string {
+	return s.strategy.Name()
 }
 
 // Метод для получения всех матчей за тот или иной период
@@ -111,3 +279,40 @@ func (s *MatchesService) HandleGetMatchesForPeriod(ctx context.Context, league,
 	return s.matchesStore.GetMatchesInPeriod(ctx, league, from, to)
 
 }
+
+// Метод для получения топовых матчей за период
+// Сортирует оценённые матчи по рейтингу и оставляет не больше limit штук
+func (s *MatchesService) HandleGetTopMatches(ctx context.Context, from, to string, limit int) ([]types.Match, error) {
+	matches, err := s.HandleGetRatedMatches(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Rating > matches[j].Rating
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// Метод для получения матчей за период, рейтинг которых посчитан текущей стратегией той же версии
+// Рейтинги другой версии формулы или профиля не сравниваются с текущими
+func (s *MatchesService) HandleGetRatedMatches(ctx context.Context, from, to string) ([]types.Match, error) {
+	all, err := s.matchesStore.GetMatchesInPeriod(ctx, "", from, to)
+	if err != nil {
+		return nil, err
+	}
+	return ratedWith(all, s.strategy.Name(), s.strategy.Version()), nil
+}
+
+// Матчи, рейтинг которых посчитан стратегией strategy версии version
+func ratedWith(matches []types.Match, strategy, version string) []types.Match {
+	var rated []types.Match
+	for _, match := range matches {
+		if match.RatedBy() == strategy && match.RatingVersion == version {
+			rated = append(rated, match)
+		}
+	}
+	return rated
+}
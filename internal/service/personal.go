@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/cache"
+	mongoRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
+	pgRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/postgres"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Надбавки к рейтингу матча в персональном топе
+	followedTeamBoost   = 0.6
+	followedLeagueBoost = 0.3
+	// Максимальная надбавка за лигу, которую пользователь открывает чаще всего;
+	// умножается на долю лиги среди всех открытий
+	leagueUsageBoost = 0.3
+	// За какой период учитываются открытия лиг и сколько их нужно, чтобы им доверять
+	leagueUsagePeriod = 30 * 24 * time.Hour
+	minLeagueUsage    = 5
+	// Сколько живёт персональный топ в кэше; задача обновления матчей сбрасывает его раньше
+	personalTopTTL = time.Hour
+	// Период, за который строится топ
+	personalTopDays = 7
+)
+
+// Префикс ключей персональных топов в Redis
+const PersonalTopCachePrefix = "top_matches:"
+
+// Соревнования, на которые можно подписаться; названия как в Match.Competition.Name
+var personalLeagues = []string{"EPL", "LaLiga", "Bundesliga", "SerieA", "Ligue1", "UCL", "UEL", "Primeira", "Eredivisie"}
+
+// Другие написания соревнований, в том числе параметры кнопок таблиц
+var personalLeagueAliases = map[string]string{
+	"apl":             "EPL",
+	"pl":              "EPL",
+	"premierleague":   "EPL",
+	"primeradivision": "LaLiga",
+	"cl":              "UCL",
+	"championsleague": "UCL",
+	"лч":              "UCL",
+	"el":              "UEL",
+	"europaleague":    "UEL",
+	"лигаевропы":      "UEL",
+	"лигачемпионов":   "UCL",
+	"primeiraliga":    "Primeira",
+}
+
+// Интерфейс кэша персональных топов; реализуется cache.RedisClient
+type PersonalTopCache interface {
+	SetBytes(ctx context.Context, key string, value []byte, expiration time.Duration) error
+	GetBytes(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// PersonalizationService строит топ матчей с учётом подписок, скрытых команд и лиг
+// и истории открытий лиг пользователем
+// Реализует PersonalDataStore: закэшированный топ содержит названия отслеживаемых команд и лиг
+type PersonalizationService struct {
+	matchesService *MatchesService
+	prefStore      pgRepo.PreferenceStore
+	eventStore     pgRepo.EventStore
+	teamsStore     mongoRepo.TeamsSearchStore
+	cache          PersonalTopCache
+	now            func() time.Time
+}
+
+// Конструктор для создания нового экземпляра PersonalizationService
+func NewPersonalizationService(matchesService *MatchesService, prefStore pgRepo.PreferenceStore, eventStore pgRepo.EventStore, teamsStore mongoRepo.TeamsSearchStore, cache PersonalTopCache) *PersonalizationService {
+	return &PersonalizationService{
+		matchesService: matchesService,
+		prefStore:      prefStore,
+		eventStore:     eventStore,
+		teamsStore:     teamsStore,
+		cache:          cache,
+		now:            time.Now,
+	}
+}
+
+// Метод для получения персонального топа матчей на неделю
+// Результат кэшируется в Redis для каждого пользователя отдельно
+func (s *PersonalizationService) HandleGetTopMatches(ctx context.Context, telegramID int64, limit int) ([]types.PersonalMatch, error) {
+	key := personalTopKey(telegramID)
+	data, err := s.cache.GetBytes(ctx, key)
+	if err == nil {
+		var top []types.PersonalMatch
+		if err := json.Unmarshal(data, &top); err == nil {
+			return top, nil
+		}
+	} else if !errors.Is(err, cache.ErrCacheMiss) {
+		logrus.WithField("cache_key", key).Warnf("Cache error: %v", err)
+	}
+
+	from := s.now()
+	to := from.AddDate(0, 0, personalTopDays)
+	matches, err := s.matchesService.HandleGetRatedMatches(ctx, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := s.prefStore.GetPreferences(ctx, telegramID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting preferences: %w", err)
+	}
+	usage, err := s.eventStore.GetLeagueUsage(ctx, telegramID, from.Add(-leagueUsagePeriod))
+	if err != nil {
+		return nil, fmt.Errorf("error getting league usage: %w", err)
+	}
+
+	top := RankMatches(matches, prefs, usage, limit)
+	if data, err := json.Marshal(top); err == nil {
+		if err := s.cache.SetBytes(ctx, key, data, personalTopTTL); err != nil {
+			logrus.WithField("cache_key", key).Warnf("Failed to cache personal top: %v", err)
+		}
+	}
+	return top, nil
+}
+
+// Метод для получения предпочтений пользователя
+func (s *PersonalizationService) HandleGetPreferences(ctx context.Context, telegramID int64) ([]types.Preference, error) {
+	return s.prefStore.GetPreferences(ctx, telegramID)
+}
+
+// Метод для подписки на команду или лигу (mode = types.PreferenceFollow)
+// либо для их скрытия (mode = types.PreferenceHide)
+// Возвращает nil, если по запросу не нашлось ни лиги, ни команды
+func (s *PersonalizationService) HandleSavePreference(ctx context.Context, telegramID int64, query, mode string) (*types.Preference, error) {
+	pref, err := s.resolve(ctx, query)
+	if err != nil || pref == nil {
+		return nil, err
+	}
+	pref.TelegramID = telegramID
+	pref.Mode = mode
+	if err := s.prefStore.SavePreference(ctx, *pref); err != nil {
+		return nil, fmt.Errorf("error saving preference: %w", err)
+	}
+	return pref, s.invalidate(ctx, telegramID)
+}
+
+// Метод для удаления подписки или скрытия
+// Возвращает nil, если такой команды или лиги нет в предпочтениях пользователя
+func (s *PersonalizationService) HandleDeletePreference(ctx context.Context, telegramID int64, query string) (*types.Preference, error) {
+	pref, err := s.resolve(ctx, query)
+	if err != nil || pref == nil {
+		return nil, err
+	}
+	deleted, err := s.prefStore.DeletePreference(ctx, telegramID, pref.Kind, pref.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting preference: %w", err)
+	}
+	if !deleted {
+		return nil, nil
+	}
+	return pref, s.invalidate(ctx, telegramID)
+}
+
+// Находит лигу или команду по запросу пользователя; лиги проверяются первыми
+func (s *PersonalizationService) resolve(ctx context.Context, query string) (*types.Preference, error) {
+	if league, ok := PersonalLeague(query); ok {
+		return &types.Preference{Kind: types.PreferenceLeague, Value: league, Label: league}, nil
+	}
+	team, err := findTeam(ctx, s.teamsStore, query)
+	if err != nil || team == nil {
+		return nil, err
+	}
+	return &types.Preference{Kind: types.PreferenceTeam, Value: strconv.Itoa(team.ID), Label: team.Name}, nil
+}
+
+// Сбрасывает кэш персонального топа после изменения предпочтений
+func (s *PersonalizationService) invalidate(ctx context.Context, telegramID int64) error {
+	if err := s.cache.Delete(ctx, personalTopKey(telegramID)); err != nil {
+		return fmt.Errorf("error invalidating personal top: %w", err)
+	}
+	return nil
+}
+
+// Section возвращает название раздела персонального топа в JSON-выгрузке
+func (s *PersonalizationService) Section() string {
+	return "personal_top"
+}
+
+// ExportUserData выгружает закэшированный персональный топ пользователя; nil, если его нет в кэше
+func (s *PersonalizationService) ExportUserData(ctx context.Context, telegramID int64) (interface{}, error) {
+	data, err := s.cache.GetBytes(ctx, personalTopKey(telegramID))
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting personal top: %w", err)
+	}
+	var top []types.PersonalMatch
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, fmt.Errorf("error decoding personal top: %w", err)
+	}
+	return top, nil
+}
+
+// DeleteUserData удаляет закэшированный персональный топ пользователя
+func (s *PersonalizationService) DeleteUserData(ctx context.Context, telegramID int64) error {
+	return s.invalidate(ctx, telegramID)
+}
+
+func personalTopKey(telegramID int64) string {
+	return PersonalTopCachePrefix + strconv.FormatInt(telegramID, 10)
+}
+
+// PersonalLeague возвращает название соревнования по написанию пользователя или параметру кнопки
+func PersonalLeague(query string) (string, bool) {
+	key := strings.ToLower(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.TrimSpace(query)))
+	if key == "" {
+		return "", false
+	}
+	for _, league := range personalLeagues {
+		if strings.ToLower(league) == key {
+			return league, true
+		}
+	}
+	league, ok := personalLeagueAliases[key]
+	return league, ok
+}
+
+// RankMatches строит персональный топ: убирает скрытые команды и лиги,
+// поднимает матчи отслеживаемых команд, лиг и часто открываемых лиг
+// Без предпочтений и истории порядок совпадает с общим топом по рейтингу
+func RankMatches(matches []types.Match, prefs []types.Preference, usage []types.UsageCount, limit int) []types.PersonalMatch {
+	followedTeams := make(map[int]string)
+	hiddenTeams := make(map[int]bool)
+	followedLeagues := make(map[string]bool)
+	hiddenLeagues := make(map[string]bool)
+	for _, p := range prefs {
+		switch p.Kind {
+		case types.PreferenceTeam:
+			id, err := strconv.Atoi(p.Value)
+			if err != nil {
+				continue
+			}
+			if p.Mode == types.PreferenceHide {
+				hiddenTeams[id] = true
+			} else {
+				followedTeams[id] = p.Label
+			}
+		case types.PreferenceLeague:
+			if p.Mode == types.PreferenceHide {
+				hiddenLeagues[p.Value] = true
+			} else {
+				followedLeagues[p.Value] = true
+			}
+		}
+	}
+	shares := leagueShares(usage)
+
+	top := make([]types.PersonalMatch, 0, len(matches))
+	for _, match := range matches {
+		league := match.Competition.Name
+		if hiddenLeagues[league] || hiddenTeams[match.HomeTeam.ID] || hiddenTeams[match.AwayTeam.ID] {
+			continue
+		}
+		var (
+			boost   float64
+			reasons []string
+		)
+		if name, ok := followedTeams[match.HomeTeam.ID]; ok {
+			boost += followedTeamBoost
+			reasons = append(reasons, fmt.Sprintf("вы следите за %s +%.0f%%", name, followedTeamBoost*100))
+		} else if name, ok := followedTeams[match.AwayTeam.ID]; ok {
+			boost += followedTeamBoost
+			reasons = append(reasons, fmt.Sprintf("вы следите за %s +%.0f%%", name, followedTeamBoost*100))
+		}
+		if followedLeagues[league] {
+			boost += followedLeagueBoost
+			reasons = append(reasons, fmt.Sprintf("вы следите за %s +%.0f%%", league, followedLeagueBoost*100))
+		}
+		if b := leagueUsageBoost * shares[league]; b >= 0.01 {
+			boost += b
+			reasons = append(reasons, fmt.Sprintf("вы часто открываете %s +%.0f%%", league, b*100))
+		}
+		top = append(top, types.PersonalMatch{
+			Match:     match,
+			Relevance: match.Rating * (1 + boost),
+			Reasons:   reasons,
+		})
+	}
+
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].Relevance > top[j].Relevance
+	})
+	if len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
+
+// Доля открытий каждой лиги; при слишком короткой истории возвращает пустую мапу
+func leagueShares(usage []types.UsageCount) map[string]float64 {
+	total := 0
+	for _, u := range usage {
+		total += u.Count
+	}
+	shares := make(map[string]float64)
+	if total < minLeagueUsage {
+		return shares
+	}
+	for _, u := range usage {
+		if league, ok := PersonalLeague(u.Name); ok {
+			shares[league] += float64(u.Count) / float64(total)
+		}
+	}
+	return shares
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/cache"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+func personalMatch(id, home, away int, league string, rating float64) types.Match {
+	m := playedMatch(id, "2025-03-01T15:00:00Z", home, away, 0, 0)
+	m.Competition.Name = league
+	m.Rating = rating
+	return m
+}
+
+func TestRankMatches(t *testing.T) {
+	matches := []types.Match{
+		personalMatch(1, 10, 11, "EPL", 0.9),
+		personalMatch(2, 20, 21, "LaLiga", 0.8),
+		personalMatch(3, 30, 31, "SerieA", 0.6),
+		personalMatch(4, 40, 41, "Bundesliga", 0.5),
+	}
+
+	plain := RankMatches(matches, nil, nil, 3)
+	if len(plain) != 3 || plain[0].Match.ID != 1 || plain[1].Match.ID != 2 || plain[2].Match.ID != 3 {
+		t.Fatalf("without preferences the order should follow the rating, got %+v", plain)
+	}
+
+	prefs := []types.Preference{
+		{Kind: types.PreferenceTeam, Value: "41", Label: "Bayern", Mode: types.PreferenceFollow},
+		{Kind: types.PreferenceLeague, Value: "EPL", Label: "EPL", Mode: types.PreferenceHide},
+		{Kind: types.PreferenceTeam, Value: "21", Label: "Barcelona", Mode: types.PreferenceHide},
+	}
+	usage := []types.UsageCount{{Name: "SerieA", Count: 9}, {Name: "CL", Count: 1}}
+	top := RankMatches(matches, prefs, usage, 10)
+	if len(top) != 2 {
+		t.Fatalf("hidden league and team should be filtered out, got %+v", top)
+	}
+	if top[0].Match.ID != 4 || len(top[0].Reasons) == 0 {
+		t.Errorf("followed team should be first with a reason, got %+v", top[0])
+	}
+	if top[1].Match.ID != 3 || top[1].Relevance <= 0.6 {
+		t.Errorf("often opened league should be boosted, got %+v", top[1])
+	}
+}
+
+func TestRankMatchesIgnoresShortHistory(t *testing.T) {
+	matches := []types.Match{personalMatch(1, 1, 2, "EPL", 0.5)}
+	top := RankMatches(matches, nil, []types.UsageCount{{Name: "EPL", Count: minLeagueUsage - 1}}, 10)
+	if top[0].Relevance != 0.5 {
+		t.Errorf("a short usage history should not change relevance, got %.2f", top[0].Relevance)
+	}
+}
+
+func TestPersonalLeague(t *testing.T) {
+	for query, want := range map[string]string{"EPL": "EPL", "premier league": "EPL", "CL": "UCL", "serie a": "SerieA", "Ligue 1": "Ligue1"} {
+		if got, ok := PersonalLeague(query); !ok || got != want {
+			t.Errorf("PersonalLeague(%q) = %q, %v; want %q", query, got, ok, want)
+		}
+	}
+	if _, ok := PersonalLeague("Arsenal"); ok {
+		t.Error("team names should not resolve to a league")
+	}
+}
+
+// Кэш персональных топов в памяти
+type memoryTopCache map[string][]byte
+
+func (c memoryTopCache) SetBytes(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	c[key] = value
+	return nil
+}
+
+func (c memoryTopCache) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	value, ok := c[key]
+	if !ok {
+		return nil, cache.ErrCacheMiss
+	}
+	return value, nil
+}
+
+func (c memoryTopCache) Delete(ctx context.Context, key string) error {
+	delete(c, key)
+	return nil
+}
+
+func TestPersonalTopIsPersonalData(t *testing.T) {
+	ctx := context.Background()
+	topCache := memoryTopCache{}
+	s := NewPersonalizationService(nil, nil, nil, nil, topCache)
+	topCache[personalTopKey(7)] = []byte(`[{"reasons": ["вы следите за Arsenal +60%"]}]`)
+
+	exported, err := s.ExportUserData(ctx, 7)
+	if top, ok := exported.([]types.PersonalMatch); err != nil || !ok || len(top) != 1 {
+		t.Fatalf("ExportUserData = %v, %v; want the cached top", exported, err)
+	}
+	if err := s.DeleteUserData(ctx, 7); err != nil {
+		t.Fatal(err)
+	}
+	if exported, err := s.ExportUserData(ctx, 7); err != nil || exported != nil {
+		t.Errorf("after delete ExportUserData = %v, %v; want nothing", exported, err)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Параметры модели Пуассона
+const (
+	// Через сколько дней вес результата падает вдвое
+	poissonHalfLifeDays = 180.0
+	// Сколько «средних» матчей добавляется каждой команде, чтобы сила по паре игр не улетала в крайности
+	poissonPriorWeight = 3.0
+	// До скольки голов у каждой команды перебираются счета
+	poissonMaxGoals = 10
+)
+
+// Взвешенные голы команды отдельно дома и в гостях
+type teamGoals struct {
+	homeScored, homeConceded, homeWeight float64
+	awayScored, awayConceded, awayWeight float64
+}
+
+// PoissonModel - силы атаки и обороны команд дома и в гостях
+// Ожидаемые голы хозяев = средние голы хозяев × атака хозяев дома × оборона гостей в гостях, у гостей симметрично
+type PoissonModel struct {
+	teams   map[int]*teamGoals
+	homeAvg float64
+	awayAvg float64
+}
+
+// FitPoisson оценивает силы команд по сыгранным до now матчам
+// Старые результаты весят меньше: вес падает вдвое каждые poissonHalfLifeDays дней
+func FitPoisson(matches []types.Match, now time.Time) *PoissonModel {
+	m := &PoissonModel{teams: make(map[int]*teamGoals)}
+	var homeGoals, awayGoals, weight float64
+	for _, match := range matches {
+		if match.Status != "FINISHED" {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, match.UTCDate)
+		if err != nil || date.After(now) {
+			continue
+		}
+		w := math.Pow(0.5, now.Sub(date).Hours()/24/poissonHalfLifeDays)
+		hg, ag := float64(match.Score.FullTime.Home), float64(match.Score.FullTime.Away)
+
+		home := m.team(match.HomeTeam.ID)
+		home.homeScored += w * hg
+		home.homeConceded += w * ag
+		home.homeWeight += w
+		away := m.team(match.AwayTeam.ID)
+		away.awayScored += w * ag
+		away.awayConceded += w * hg
+		away.awayWeight += w
+
+		homeGoals += w * hg
+		awayGoals += w * ag
+		weight += w
+	}
+	if weight > 0 {
+		m.homeAvg = homeGoals / weight
+		m.awayAvg = awayGoals / weight
+	}
+	return m
+}
+
+func (m *PoissonModel) team(id int) *teamGoals {
+	t, ok := m.teams[id]
+	if !ok {
+		t = &teamGoals{}
+		m.teams[id] = t
+	}
+	return t
+}
+
+// Predict возвращает прогноз матча; false, если ни об одной из команд нет данных
+func (m *PoissonModel) Predict(homeID, awayID int) (*types.Prediction, bool) {
+	if m.homeAvg <= 0 || m.awayAvg <= 0 {
+		return nil, false
+	}
+	home, homeKnown := m.teams[homeID]
+	away, awayKnown := m.teams[awayID]
+	if !homeKnown && !awayKnown {
+		return nil, false
+	}
+	if home == nil {
+		home = &teamGoals{}
+	}
+	if away == nil {
+		away = &teamGoals{}
+	}
+
+	homeAttack := shrink(home.homeScored, home.homeWeight, m.homeAvg) / m.homeAvg
+	homeDefence := shrink(home.homeConceded, home.homeWeight, m.awayAvg) / m.awayAvg
+	awayAttack := shrink(away.awayScored, away.awayWeight, m.awayAvg) / m.awayAvg
+	awayDefence := shrink(away.awayConceded, away.awayWeight, m.homeAvg) / m.homeAvg
+
+	p := &types.Prediction{
+		HomeExpected: m.homeAvg * homeAttack * awayDefence,
+		AwayExpected: m.awayAvg * awayAttack * homeDefence,
+	}
+	homeProbs := poissonProbabilities(p.HomeExpected)
+	awayProbs := poissonProbabilities(p.AwayExpected)
+	for h, ph := range homeProbs {
+		for a, pa := range awayProbs {
+			prob := ph * pa
+			switch {
+			case h > a:
+				p.HomeWin += prob
+			case h == a:
+				p.Draw += prob
+			default:
+				p.AwayWin += prob
+			}
+			if prob > p.ScoreProbability {
+				p.ScoreProbability = prob
+				p.HomeGoals, p.AwayGoals = h, a
+			}
+		}
+	}
+	// Счета больше poissonMaxGoals отброшены, поэтому вероятности нормируются
+	if total := p.HomeWin + p.Draw + p.AwayWin; total > 0 {
+		p.HomeWin /= total
+		p.Draw /= total
+		p.AwayWin /= total
+	}
+	p.Closeness = 1 - math.Abs(p.HomeWin-p.AwayWin)
+	return p, true
+}
+
+// Среднее за матч с поправкой к среднему по лиге для команд с короткой историей
+func shrink(goals, weight, avg float64) float64 {
+	return (goals + poissonPriorWeight*avg) / (weight + poissonPriorWeight)
+}
+
+// Вероятности забить 0..poissonMaxGoals голов при ожидании lambda
+func poissonProbabilities(lambda float64) []float64 {
+	probs := make([]float64, poissonMaxGoals+1)
+	probs[0] = math.Exp(-lambda)
+	for k := 1; k <= poissonMaxGoals; k++ {
+		probs[k] = probs[k-1] * lambda / float64(k)
+	}
+	return probs
+}
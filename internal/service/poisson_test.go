@@ -0,0 +1,60 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+func TestPoissonPrediction(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	var matches []types.Match
+	// Команда 1 крупно обыгрывает всех, команды 2 и 3 равны
+	for i := 0; i < 10; i++ {
+		date := now.AddDate(0, 0, -7*(i+1)).Format(time.RFC3339)
+		matches = append(matches,
+			playedMatch(i*3+1, date, 1, 2, 3, 0),
+			playedMatch(i*3+2, date, 3, 1, 0, 2),
+			playedMatch(i*3+3, date, 2, 3, 1, 1),
+		)
+	}
+	model := FitPoisson(matches, now)
+
+	strong, ok := model.Predict(1, 2)
+	if !ok {
+		t.Fatal("teams with history should get a prediction")
+	}
+	if sum := strong.HomeWin + strong.Draw + strong.AwayWin; math.Abs(sum-1) > 1e-9 {
+		t.Errorf("probabilities should sum to 1, got %.4f", sum)
+	}
+	if strong.HomeWin <= strong.AwayWin || strong.HomeGoals <= strong.AwayGoals {
+		t.Errorf("the dominant team should be the favourite, got %+v", strong)
+	}
+
+	even, _ := model.Predict(2, 3)
+	if even.Closeness <= strong.Closeness {
+		t.Errorf("an even pair should be closer than a mismatch: %.2f <= %.2f", even.Closeness, strong.Closeness)
+	}
+
+	if _, ok := model.Predict(98, 99); ok {
+		t.Error("unknown teams should not get a prediction")
+	}
+}
+
+func TestPoissonIgnoresFutureAndDecays(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	future := playedMatch(1, now.AddDate(0, 0, 1).Format(time.RFC3339), 1, 2, 9, 0)
+	if _, ok := FitPoisson([]types.Match{future}, now).Predict(1, 2); ok {
+		t.Error("matches after now must not be used")
+	}
+
+	// Свежие поражения весят больше старых побед
+	old := playedMatch(1, now.AddDate(-1, 0, 0).Format(time.RFC3339), 1, 2, 3, 0)
+	recent := playedMatch(2, now.AddDate(0, 0, -3).Format(time.RFC3339), 1, 2, 0, 3)
+	p, _ := FitPoisson([]types.Match{old, recent}, now).Predict(1, 2)
+	if p.HomeWin >= p.AwayWin {
+		t.Errorf("recent results should dominate, got %+v", p)
+	}
+}
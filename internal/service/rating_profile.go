@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/config"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// RatingProfiles хранит текущий профиль рейтинга и умеет перечитывать его из файла на лету
+// Безопасен для одновременного использования из задач планировщика
+type RatingProfiles struct {
+	path string
+
+	mu      sync.RWMutex
+	current types.RatingProfile
+	modTime time.Time
+}
+
+// Конструктор для создания хранилища профиля рейтинга
+// Если path пустой, используется встроенный профиль types.DefaultRatingProfile
+func NewRatingProfiles(path string) (*RatingProfiles, error) {
+	p := &RatingProfiles{path: path, current: types.DefaultRatingProfile()}
+	if path == "" {
+		return p, nil
+	}
+	if _, err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Current возвращает текущий профиль
+func (p *RatingProfiles) Current() types.RatingProfile {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.current
+}
+
+// Reload перечитывает файл профиля, если он изменился с последней загрузки
+// При ошибке чтения или валидации остаётся предыдущий профиль
+// Возвращает true, если профиль был заменён
+func (p *RatingProfiles) Reload() (bool, error) {
+	if p.path == "" {
+		return false, nil
+	}
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return false, fmt.Errorf("error reading rating profile %s: %w", p.path, err)
+	}
+
+	p.mu.RLock()
+	unchanged := info.ModTime().Equal(p.modTime)
+	p.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	profile, err := config.LoadRatingProfile(p.path)
+	if err != nil {
+		return false, err
+	}
+
+	p.mu.Lock()
+	p.current = *profile
+	p.modTime = info.ModTime()
+	p.mu.Unlock()
+	return true, nil
+}
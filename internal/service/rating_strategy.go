@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// RatingStrategy - способ расчёта рейтинга матча
+// Name попадает в Match.RatingStrategy, Version - в Match.RatingVersion,
+// поэтому рейтинги разных стратегий никогда не сравниваются между собой
+type RatingStrategy interface {
+	Name() string
+	Version() string
+	Rate(ctx context.Context, match types.Match, calculator Calculator) (*types.RatingBreakdown, error)
+}
+
+// ErrUnknownLeagues - лиги команд неизвестны, и оценить матч нельзя
+// Такой матч не получает рейтинг и пропускается при сохранении
+var ErrUnknownLeagues = errors.New("unknown leagues")
+
+// RatingStrategies - реестр стратегий, из которого updater и seed_matches выбирают стратегию по имени
+type RatingStrategies struct {
+	strategies map[string]RatingStrategy
+}
+
+// Конструктор для создания реестра стратегий рейтинга
+func NewRatingStrategies(strategies ...RatingStrategy) *RatingStrategies {
+	r := &RatingStrategies{strategies: make(map[string]RatingStrategy, len(strategies))}
+	for _, s := range strategies {
+		r.strategies[s.Name()] = s
+	}
+	return r
+}
+
+// Get возвращает стратегию по имени; пустое имя означает стратегию по умолчанию
+func (r *RatingStrategies) Get(name string) (RatingStrategy, error) {
+	if name == "" {
+		name = types.LegacyRatingStrategy
+	}
+	s, ok := r.strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown rating strategy %q, available: %s", name, strings.Join(r.Names(), ", "))
+	}
+	return s, nil
+}
+
+// Names возвращает имена зарегистрированных стратегий в алфавитном порядке
+func (r *RatingStrategies) Names() []string {
+	names := make([]string, 0, len(r.strategies))
+	for name := range r.strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Версии алгоритмов; увеличиваются при изменении формулы в коде
+const (
+	heuristicStrategyVersion = 5
+	balanceStrategyVersion   = 3
+	eloStrategyVersion       = 2
+)
+
+// Версия стратегии на основе профиля: версия формулы, профиль с его версией и хэш содержимого
+func profileVersion(codeVersion int, profile types.RatingProfile) string {
+	return fmt.Sprintf("%d-%s.%d-%s", codeVersion, profile.Name, profile.Version, profile.Checksum())
+}
+
+// HeuristicStrategy - исходный расчёт рейтинга: позиции в таблице, вес лиг, форма и бонусы,
+// в том числе за зрелищность команд
+type HeuristicStrategy struct {
+	profiles *RatingProfiles
+}
+
+// Конструктор для создания эвристической стратегии
+func NewHeuristicStrategy(profiles *RatingProfiles) *HeuristicStrategy {
+	return &HeuristicStrategy{profiles: profiles}
+}
+
+func (h *HeuristicStrategy) Name() string {
+	return "heuristic"
+}
+
+func (h *HeuristicStrategy) Version() string {
+	return profileVersion(heuristicStrategyVersion, h.profiles.Current())
+}
+
+// Rate считает рейтинг по позициям, весу лиг, форме и бонусам профиля
+func (h *HeuristicStrategy) Rate(ctx context.Context, match types.Match, calculator Calculator) (*types.RatingBreakdown, error) {
+	profile := h.profiles.Current()
+	b := &types.RatingBreakdown{Profile: profile.Name, ProfileVersion: profile.Version}
+
+	// 0) Стадия кубкового турнира
+	b.StageBonus, b.FirstLegScore = GetStageBonus(ctx, calculator, match, profile)
+	cupMatch := b.StageBonus > 0
+
+	// 1) Сила команд по позициям
+	// В кубках часто играют команды из лиг, которых нет в базе: их сила считается средней
+	homeStrength, awayStrength, err := CalculatePositionOfTeams(ctx, calculator, match)
+	if err != nil {
+		if !cupMatch {
+			return nil, fmt.Errorf("error calculating team strengths: %s", err)
+		}
+		homeStrength, awayStrength = neutralStrength, neutralStrength
+	}
+	b.HomeStrength, b.AwayStrength = homeStrength, awayStrength
+
+	// 2) Лиги и вес; для кубкового матча с неизвестными лигами берётся вес самого турнира
+	homeLeague, awayLeague, err := GetLeaguesForTeams(ctx, calculator, match.HomeTeam.ID, match.AwayTeam.ID)
+	leaguesKnown := err == nil && homeLeague != "" && awayLeague != ""
+	switch {
+	case leaguesKnown:
+		b.LeagueWeight = (profile.LeagueNorm[homeLeague] + profile.LeagueNorm[awayLeague]) / 2.0
+	case cupMatch:
+		b.LeagueWeight = profile.LeagueNorm[competitionLeagueKeys[CompetitionCode(match)]]
+	default:
+		return nil, fmt.Errorf("%w: %q (%d) - %q (%d)", ErrUnknownLeagues, homeLeague, match.HomeTeam.ID, awayLeague, match.AwayTeam.ID)
+	}
+
+	// 3) Форма и зрелищность команд; форма берётся по первым FormMatches из тех же матчей
+	recentMatchesHome, err := calculator.HandleGetRecentMatches(ctx, match.HomeTeam.ID, EntertainmentMatches)
+	if err != nil {
+		log.Printf("Error getting recent matches for home team %d: %v", match.HomeTeam.ID, err)
+	}
+	recentMatchesAway, err := calculator.HandleGetRecentMatches(ctx, match.AwayTeam.ID, EntertainmentMatches)
+	if err != nil {
+		log.Printf("Error getting recent matches for away team %d: %v", match.AwayTeam.ID, err)
+	}
+	homeForm := AnalyzeForm(recentMatchesHome, match.HomeTeam.ID)
+	awayForm := AnalyzeForm(recentMatchesAway, match.AwayTeam.ID)
+	b.HomeForm, b.AwayForm = homeForm.Rating, awayForm.Rating
+	b.HomeLastFive, b.AwayLastFive = homeForm.LastFive, awayForm.LastFive
+	b.FormFactor = (b.HomeForm + b.AwayForm) / 2.0
+	b.HomeEntertainment = TeamEntertainment(match.HomeTeam.ID, recentMatchesHome).Index
+	b.AwayEntertainment = TeamEntertainment(match.AwayTeam.ID, recentMatchesAway).Index
+
+	// 4) Бонусы
+	b.DerbyBonus = GetDerbyBonus(ctx, calculator, match)
+	if leaguesKnown && homeLeague != awayLeague {
+		b.CrossLeagueBonus = profile.CrossLeagueBonus
+	}
+	b.Stakes, err = GetStakes(ctx, calculator, match, homeLeague, awayLeague)
+	if err != nil {
+		log.Printf("Error calculating stakes for match %d: %v", match.ID, err)
+	}
+	b.StakesBonus = b.Stakes * profile.StakesBonus
+	// Бонус получают только пары зрелищнее средней
+	fun := (b.HomeEntertainment + b.AwayEntertainment) / 2.0
+	b.EntertainmentBonus = math.Max(0, (fun-entertainmentNeutral)*2) * profile.EntertainmentBonus
+
+	// 5) Финальный рейтинг
+	b.StrengthPart = (b.HomeStrength + b.AwayStrength) / 2.0 * profile.Weights.Position
+	b.LeaguePart = b.LeagueWeight * profile.Weights.League
+	b.FormPart = b.FormFactor * profile.Weights.Form
+	b.BaseRating = b.StrengthPart + b.LeaguePart + b.FormPart
+	rating := b.BaseRating * (1 + b.DerbyBonus + b.StageBonus + b.CrossLeagueBonus + b.StakesBonus + b.EntertainmentBonus)
+
+	// 6) Ограничение и минимальное значение
+	if rating > profile.MaxRating {
+		rating = profile.MaxRating
+	}
+	if rating < profile.MinRating {
+		rating = profile.MinRating
+	}
+	b.Rating = rating
+
+	return b, nil
+}
+
+// BalanceStrategy ставит выше равные пары сильных команд:
+// рейтинг - вес лиги, умноженный на среднее из силы команд и их равенства
+// Равенство берётся из прогноза модели Пуассона, а без прогноза - из разницы мест в таблице
+// Не учитывает форму; из бонусов учитывает только турнирную значимость
+type BalanceStrategy struct {
+	profiles *RatingProfiles
+}
+
+// Конструктор для создания стратегии баланса сил
+func NewBalanceStrategy(profiles *RatingProfiles) *BalanceStrategy {
+	return &BalanceStrategy{profiles: profiles}
+}
+
+func (s *BalanceStrategy) Name() string {
+	return "balance"
+}
+
+func (s *BalanceStrategy) Version() string {
+	return profileVersion(balanceStrategyVersion, s.profiles.Current())
+}
+
+// Rate считает рейтинг по силе команд, их равенству и весу лиг
+func (s *BalanceStrategy) Rate(ctx context.Context, match types.Match, calculator Calculator) (*types.RatingBreakdown, error) {
+	profile := s.profiles.Current()
+	b := &types.RatingBreakdown{Profile: profile.Name, ProfileVersion: profile.Version}
+
+	homeStrength, awayStrength, err := CalculatePositionOfTeams(ctx, calculator, match)
+	if err != nil {
+		return nil, fmt.Errorf("error calculating team strengths: %s", err)
+	}
+	homeLeague, awayLeague, err := GetLeaguesForTeams(ctx, calculator, match.HomeTeam.ID, match.AwayTeam.ID)
+	if err != nil || homeLeague == "" || awayLeague == "" {
+		return nil, fmt.Errorf("%w: %q (%d) - %q (%d)", ErrUnknownLeagues, homeLeague, match.HomeTeam.ID, awayLeague, match.AwayTeam.ID)
+	}
+
+	b.HomeStrength, b.AwayStrength = homeStrength, awayStrength
+	b.LeagueWeight = (profile.LeagueNorm[homeLeague] + profile.LeagueNorm[awayLeague]) / 2.0
+	b.Closeness = 1 - math.Abs(homeStrength-awayStrength)
+	prediction, err := calculator.HandleGetPrediction(ctx, match)
+	if err != nil {
+		log.Printf("Error getting prediction for match %d: %v", match.ID, err)
+	}
+	if prediction != nil {
+		b.Prediction = prediction
+		b.Closeness = prediction.Closeness
+	}
+	b.Stakes, err = GetStakes(ctx, calculator, match, homeLeague, awayLeague)
+	if err != nil {
+		log.Printf("Error calculating stakes for match %d: %v", match.ID, err)
+	}
+	b.StakesBonus = b.Stakes * profile.StakesBonus
+
+	b.StrengthPart = (homeStrength + awayStrength) / 2.0
+	b.BaseRating = b.LeagueWeight * (b.StrengthPart + b.Closeness) / 2.0
+	rating := b.BaseRating * (1 + b.StakesBonus)
+	b.Rating = math.Max(profile.MinRating, math.Min(profile.MaxRating, rating))
+	return b, nil
+}
+
+// Сила команды, для которой нет данных в таблицах
+const neutralStrength = 0.5
+
+// Параметры нормализации Эло в силу команды от 0 до 1
+const (
+	eloStrengthFloor = 1300.0
+	eloStrengthRange = 500.0
+)
+
+// Веса частей рейтинга стратегии на Эло
+const (
+	eloStrengthWeight  = 0.5
+	eloClosenessWeight = 0.3
+	eloLeagueWeight    = 0.2
+)
+
+// EloStrategy оценивает матч по рейтингам Эло команд:
+// сила - средний Эло пары, равенство - насколько ожидаемый исход близок к 50/50
+// В отличие от позиций в таблице, Эло сравним между лигами и учитывает еврокубки
+type EloStrategy struct {
+	profiles *RatingProfiles
+}
+
+// Конструктор для создания стратегии на основе Эло
+func NewEloStrategy(profiles *RatingProfiles) *EloStrategy {
+	return &EloStrategy{profiles: profiles}
+}
+
+func (s *EloStrategy) Name() string {
+	return "elo"
+}
+
+func (s *EloStrategy) Version() string {
+	return profileVersion(eloStrategyVersion, s.profiles.Current())
+}
+
+// Rate считает рейтинг по Эло команд, весу лиг, бонусу дерби и турнирной значимости
+func (s *EloStrategy) Rate(ctx context.Context, match types.Match, calculator Calculator) (*types.RatingBreakdown, error) {
+	profile := s.profiles.Current()
+	b := &types.RatingBreakdown{Profile: profile.Name, ProfileVersion: profile.Version}
+
+	homeElo, err := calculator.HandleGetTeamElo(ctx, match.HomeTeam.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting elo for team %d: %w", match.HomeTeam.ID, err)
+	}
+	awayElo, err := calculator.HandleGetTeamElo(ctx, match.AwayTeam.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting elo for team %d: %w", match.AwayTeam.ID, err)
+	}
+	b.HomeElo, b.AwayElo = homeElo, awayElo
+	b.HomeStrength = eloStrength(homeElo)
+	b.AwayStrength = eloStrength(awayElo)
+	b.Closeness = 1 - math.Abs(2*EloExpected(homeElo, awayElo)-1)
+
+	// Лиги нужны только для веса; без них матч оценивается по Эло с нулевым весом лиги
+	homeLeague, awayLeague, err := GetLeaguesForTeams(ctx, calculator, match.HomeTeam.ID, match.AwayTeam.ID)
+	if err == nil && homeLeague != "" && awayLeague != "" {
+		b.LeagueWeight = (profile.LeagueNorm[homeLeague] + profile.LeagueNorm[awayLeague]) / 2.0
+		b.DerbyBonus = GetDerbyBonus(ctx, calculator, match)
+		b.Stakes, err = GetStakes(ctx, calculator, match, homeLeague, awayLeague)
+		if err != nil {
+			log.Printf("Error calculating stakes for match %d: %v", match.ID, err)
+		}
+		b.StakesBonus = b.Stakes * profile.StakesBonus
+	}
+
+	b.StrengthPart = (b.HomeStrength + b.AwayStrength) / 2.0 * eloStrengthWeight
+	b.LeaguePart = b.LeagueWeight * eloLeagueWeight
+	b.BaseRating = b.StrengthPart + b.Closeness*eloClosenessWeight + b.LeaguePart
+	rating := b.BaseRating * (1 + b.DerbyBonus + b.StakesBonus)
+	b.Rating = math.Max(profile.MinRating, math.Min(profile.MaxRating, rating))
+	return b, nil
+}
+
+// Переводит рейтинг Эло в силу команды от 0 до 1
+func eloStrength(elo float64) float64 {
+	return math.Max(0, math.Min(1, (elo-eloStrengthFloor)/eloStrengthRange))
+}
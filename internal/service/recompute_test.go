@@ -0,0 +1,98 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+func TestChangedStandings(t *testing.T) {
+	row := func(id, pos, points int) types.Standing {
+		s := types.Standing{Position: pos, Points: points, PlayedGames: 10}
+		s.Team.ID = id
+		return s
+	}
+	previous := []types.Standing{row(1, 1, 25), row(2, 2, 22), row(3, 3, 20)}
+	current := []types.Standing{row(1, 1, 25), row(3, 2, 23), row(2, 3, 22), row(4, 4, 18)}
+
+	if got, want := ChangedStandings(previous, current), []int{3, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedStandings = %v, want %v", got, want)
+	}
+	if got := ChangedStandings(current, current); len(got) != 0 {
+		t.Errorf("an unchanged table should report nothing, got %v", got)
+	}
+}
+
+func TestUpcomingTeamMatches(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	started := playedMatch(1, "2025-03-01T11:00:00Z", 1, 2, 0, 0)
+	started.Status = "IN_PLAY"
+	upcoming := playedMatch(2, "2025-03-02T15:00:00Z", 3, 1, 0, 0)
+	upcoming.Status = "TIMED"
+	other := playedMatch(3, "2025-03-02T17:00:00Z", 4, 5, 0, 0)
+	other.Status = "TIMED"
+
+	got := upcomingTeamMatches([]types.Match{started, upcoming, other}, []int{1}, now)
+	if len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("upcomingTeamMatches = %+v, want only match 2", got)
+	}
+}
+
+func TestNewRatingPoints(t *testing.T) {
+	at := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	same := types.Match{ID: 1, Rating: 0.6, RatingStrategy: "heuristic", RatingVersion: "4"}
+	moved := types.Match{ID: 2, Rating: 0.7, RatingStrategy: "heuristic", RatingVersion: "4"}
+	fresh := types.Match{ID: 3, Rating: 0.5, RatingStrategy: "heuristic", RatingVersion: "4"}
+	latest := map[int]types.RatingPoint{
+		1: {MatchID: 1, Rating: 0.6, Strategy: "heuristic", StrategyVersion: "4"},
+		2: {MatchID: 2, Rating: 0.65, Strategy: "heuristic", StrategyVersion: "4"},
+	}
+
+	points := newRatingPoints([]types.Match{same, moved, fresh}, latest, RatingReasonStandings, at)
+	if len(points) != 2 || points[0].MatchID != 2 || points[1].MatchID != 3 {
+		t.Fatalf("newRatingPoints = %+v, want points for matches 2 and 3", points)
+	}
+	if points[0].Reason != RatingReasonStandings || !points[0].ComputedAt.Equal(at) {
+		t.Errorf("unexpected point %+v", points[0])
+	}
+}
+
+func TestFinishedTeams(t *testing.T) {
+	played := playedMatch(1, "2025-03-01T15:00:00Z", 1, 2, 1, 0)
+	again := playedMatch(2, "2025-03-01T18:00:00Z", 2, 3, 0, 0)
+	scheduled := playedMatch(3, "2025-03-02T15:00:00Z", 4, 5, 0, 0)
+	scheduled.Status = "TIMED"
+
+	if got, want := FinishedTeams([]types.Match{played, again, scheduled}), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FinishedTeams = %v, want %v", got, want)
+	}
+}
+
+func TestUpcomingMatches(t *testing.T) {
+	played := playedMatch(1, "2025-03-01T15:00:00Z", 1, 2, 1, 0)
+	live := playedMatch(2, "2025-03-01T18:00:00Z", 3, 4, 0, 0)
+	live.Status = "IN_PLAY"
+	scheduled := playedMatch(3, "2025-03-02T15:00:00Z", 5, 6, 0, 0)
+	scheduled.Status = "TIMED"
+	postponed := playedMatch(4, "2025-03-02T18:00:00Z", 7, 8, 0, 0)
+	postponed.Status = "POSTPONED"
+
+	got := UpcomingMatches([]types.Match{played, live, scheduled, postponed})
+	if len(got) != 2 || got[0].ID != 3 || got[1].ID != 4 {
+		t.Errorf("UpcomingMatches = %+v, want matches 3 and 4", got)
+	}
+}
+
+func TestRatedWith(t *testing.T) {
+	current := types.Match{ID: 1, RatingStrategy: "heuristic", RatingVersion: "5-default.3"}
+	stale := types.Match{ID: 2, RatingStrategy: "heuristic", RatingVersion: "5-default.2"}
+	other := types.Match{ID: 3, RatingStrategy: "elo", RatingVersion: "5-default.3"}
+	legacy := types.Match{ID: 4}
+
+	got := ratedWith([]types.Match{current, stale, other, legacy}, "heuristic", "5-default.3")
+	if len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("ratedWith = %+v, want only match 1", got)
+	}
+}
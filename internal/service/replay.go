@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Ключи национальных лиг по названию соревнования в матчах (после tools.MatchFilter)
+var domesticLeaguesByName = map[string]string{
+	"EPL":        "PremierLeague",
+	"LaLiga":     "LaLiga",
+	"Bundesliga": "Bundesliga",
+	"SerieA":     "SerieA",
+	"Ligue1":     "Ligue1",
+}
+
+// Ключ национальной лиги матча из types.Leagues; false для кубков и еврокубков
+func domesticLeague(match types.Match) (string, bool) {
+	for key, league := range types.Leagues {
+		if match.Competition.Code != "" && league.Code == match.Competition.Code {
+			_, ok := types.TeamsInLeague[key]
+			return key, ok
+		}
+	}
+	key, ok := domesticLeaguesByName[match.Competition.Name]
+	return key, ok
+}
+
+// Сезон матча по дате начала: сезоны в Европе начинаются летом,
+// поэтому матч в мае 2025 относится к сезону 2024
+func matchSeason(utcDate string) int {
+	date, err := time.Parse(time.RFC3339, utcDate)
+	if err != nil {
+		return 0
+	}
+	return SeasonOf(date)
+}
+
+// Таблица лиги за один сезон
+type replayTable struct {
+	season int
+	rows   map[int]*types.Standing
+}
+
+// ReplayCalculator - Calculator для прогона истории матчей в хронологическом порядке
+// Таблицы, форма, Эло, прогнозы и первые матчи кубковых пар строятся только из результатов,
+// переданных в Observe, поэтому рейтинг матча видит лишь то, что было известно до начала
+type ReplayCalculator struct {
+	leagues   map[int]string
+	tables    map[string]*replayTable
+	history   map[int][]types.Match
+	elo       map[int]float64
+	rivalries map[[2]int]float64
+	observed  []types.Match
+	kickoff   string
+	season    int
+	// Модель Пуассона, оценённая к моменту kickoff; переоценивается при смене времени начала
+	model     *PoissonModel
+	modelTime string
+}
+
+// Конструктор для создания ReplayCalculator
+// Лиги команд берутся из расписания всех матчей: оно известно заранее и не подсказывает результатов
+func NewReplayCalculator(matches []types.Match, rivalries []types.Rivalry) *ReplayCalculator {
+	c := &ReplayCalculator{
+		leagues:   make(map[int]string),
+		tables:    make(map[string]*replayTable),
+		history:   make(map[int][]types.Match),
+		elo:       make(map[int]float64),
+		rivalries: make(map[[2]int]float64, len(rivalries)),
+	}
+	for _, m := range matches {
+		if league, ok := domesticLeague(m); ok {
+			c.leagues[m.HomeTeam.ID] = league
+			c.leagues[m.AwayTeam.ID] = league
+		}
+	}
+	for _, r := range rivalries {
+		c.rivalries[[2]int{r.TeamA, r.TeamB}] = r.Intensity
+	}
+	return c
+}
+
+// Kickoff переводит часы калькулятора на начало матча: таблицы прошлых сезонов перестают быть видны
+func (c *ReplayCalculator) Kickoff(utcDate string) {
+	c.kickoff = utcDate
+	c.season = matchSeason(utcDate)
+}
+
+// Observe учитывает результат сыгранного матча
+func (c *ReplayCalculator) Observe(match types.Match) {
+	if match.Status != "FINISHED" {
+		return
+	}
+	home, away := match.HomeTeam.ID, match.AwayTeam.ID
+	hg, ag := match.Score.FullTime.Home, match.Score.FullTime.Away
+
+	delta := EloDelta(c.teamElo(home), c.teamElo(away), hg, ag)
+	c.elo[home] = c.teamElo(home) + delta
+	c.elo[away] = c.teamElo(away) - delta
+
+	c.history[home] = append(c.history[home], match)
+	c.history[away] = append(c.history[away], match)
+	c.observed = append(c.observed, match)
+
+	league, ok := domesticLeague(match)
+	if !ok {
+		return
+	}
+	season := matchSeason(match.UTCDate)
+	table, ok := c.tables[league]
+	if !ok || table.season < season {
+		table = &replayTable{season: season, rows: make(map[int]*types.Standing)}
+		c.tables[league] = table
+	} else if table.season > season {
+		return
+	}
+	table.add(home, match.HomeTeam.Name, hg, ag)
+	table.add(away, match.AwayTeam.Name, ag, hg)
+}
+
+// Добавляет результат матча в строку команды
+func (t *replayTable) add(teamID int, name string, scored, conceded int) {
+	row, ok := t.rows[teamID]
+	if !ok {
+		row = &types.Standing{Team: types.Team{ID: teamID, Name: name}}
+		t.rows[teamID] = row
+	}
+	row.PlayedGames++
+	row.GoalsFor += scored
+	row.GoalsAgainst += conceded
+	row.GoalDifference = row.GoalsFor - row.GoalsAgainst
+	switch {
+	case scored > conceded:
+		row.Won++
+		row.Points += 3
+	case scored == conceded:
+		row.Draw++
+		row.Points++
+	default:
+		row.Lost++
+	}
+}
+
+// Рейтинг Эло команды; без истории - стартовый
+func (c *ReplayCalculator) teamElo(teamID int) float64 {
+	if elo, ok := c.elo[teamID]; ok {
+		return elo
+	}
+	return EloInitial
+}
+
+func (c *ReplayCalculator) HandleGetLeague(ctx context.Context, collectionName string, teamID int) (string, error) {
+	league, ok := c.leagues[teamID]
+	if !ok {
+		return "", fmt.Errorf("team with ID %d has no domestic league matches", teamID)
+	}
+	return league, nil
+}
+
+// Место команды в таблице текущего сезона; -1, если команда ещё не играла
+func (c *ReplayCalculator) HandleGetTeamStanding(ctx context.Context, leagueKey string, teamID int) (int, error) {
+	standings, _ := c.HandleGetStandings(ctx, leagueKey)
+	for _, s := range standings {
+		if s.Team.ID == teamID {
+			return s.Position, nil
+		}
+	}
+	return -1, nil
+}
+
+// Таблица текущего сезона по очкам, разнице и забитым мячам
+func (c *ReplayCalculator) HandleGetStandings(ctx context.Context, leagueKey string) ([]types.Standing, error) {
+	table, ok := c.tables[leagueKey]
+	if !ok || table.season != c.season {
+		return nil, nil
+	}
+	standings := make([]types.Standing, 0, len(table.rows))
+	for _, row := range table.rows {
+		standings = append(standings, *row)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.GoalDifference != b.GoalDifference {
+			return a.GoalDifference > b.GoalDifference
+		}
+		if a.GoalsFor != b.GoalsFor {
+			return a.GoalsFor > b.GoalsFor
+		}
+		return a.Team.ID < b.Team.ID
+	})
+	for i := range standings {
+		standings[i].Position = i + 1
+	}
+	return standings, nil
+}
+
+// Последние матчи команды, самые свежие первыми
+func (c *ReplayCalculator) HandleGetRecentMatches(ctx context.Context, teamID int, lastN int) ([]types.Match, error) {
+	history := c.history[teamID]
+	recent := make([]types.Match, 0, lastN)
+	for i := len(history) - 1; i >= 0 && len(recent) < lastN; i-- {
+		recent = append(recent, history[i])
+	}
+	return recent, nil
+}
+
+// Первый матч кубковой пары среди уже сыгранных матчей
+func (c *ReplayCalculator) HandleGetFirstLeg(ctx context.Context, match types.Match) (*types.Match, error) {
+	history := c.history[match.HomeTeam.ID]
+	for i := len(history) - 1; i >= 0; i-- {
+		m := history[i]
+		if m.Competition.ID == match.Competition.ID && m.Stage == match.Stage &&
+			m.HomeTeam.ID == match.AwayTeam.ID && m.AwayTeam.ID == match.HomeTeam.ID {
+			return &m, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *ReplayCalculator) HandleGetRivalryIntensity(ctx context.Context, homeTeamID, awayTeamID int) (float64, error) {
+	teamA, teamB := types.RivalryPair(homeTeamID, awayTeamID)
+	return c.rivalries[[2]int{teamA, teamB}], nil
+}
+
+func (c *ReplayCalculator) HandleGetTeamElo(ctx context.Context, teamID int) (float64, error) {
+	return c.teamElo(teamID), nil
+}
+
+// Прогноз по модели, оценённой только по уже сыгранным матчам
+func (c *ReplayCalculator) HandleGetPrediction(ctx context.Context, match types.Match) (*types.Prediction, error) {
+	if c.model == nil || c.modelTime != c.kickoff {
+		now, err := time.Parse(time.RFC3339, c.kickoff)
+		if err != nil {
+			return nil, nil
+		}
+		c.model = FitPoisson(c.observed, now)
+		c.modelTime = c.kickoff
+	}
+	prediction, ok := c.model.Predict(match.HomeTeam.ID, match.AwayTeam.ID)
+	if !ok {
+		return nil, nil
+	}
+	return prediction, nil
+}
+
+// Фаворит матча до начала: 1 - хозяева, -1 - гости, 0 - неизвестно
+// Если обе команды уже играли в одной таблице, фаворит - та, что выше; иначе - по Эло
+func (c *ReplayCalculator) favourite(ctx context.Context, match types.Match) int {
+	home, away := match.HomeTeam.ID, match.AwayTeam.ID
+	if league, ok := domesticLeague(match); ok {
+		homePos, _ := c.HandleGetTeamStanding(ctx, league, home)
+		awayPos, _ := c.HandleGetTeamStanding(ctx, league, away)
+		if homePos > 0 && awayPos > 0 {
+			if homePos < awayPos {
+				return 1
+			}
+			return -1
+		}
+	}
+	_, homeKnown := c.elo[home]
+	_, awayKnown := c.elo[away]
+	if !homeKnown && !awayKnown {
+		return 0
+	}
+	if EloExpected(c.teamElo(home), c.teamElo(away)) >= 0.5 {
+		return 1
+	}
+	return -1
+}
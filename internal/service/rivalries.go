@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	mongoRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Коллекции, в которых ищутся команды: сначала все команды лиг, затем участники Лиги чемпионов
+var teamSearchCollections = []string{"Teams", "ChampionsLeague"}
+
+// RivalryService управляет реестром дерби
+type RivalryService struct {
+	rivalryStore mongoRepo.RivalryStore
+	teamsStore   mongoRepo.TeamsSearchStore
+}
+
+// Конструктор для создания нового экземпляра RivalryService
+func NewRivalryService(rivalryStore mongoRepo.RivalryStore, teamsStore mongoRepo.TeamsSearchStore) *RivalryService {
+	return &RivalryService{
+		rivalryStore: rivalryStore,
+		teamsStore:   teamsStore,
+	}
+}
+
+// Метод для получения всех дерби
+func (s *RivalryService) HandleGetRivalries(ctx context.Context) ([]types.Rivalry, error) {
+	return s.rivalryStore.GetRivalries(ctx)
+}
+
+// Метод для получения дерби пары команд; возвращает nil, если пара не дерби
+func (s *RivalryService) HandleGetRivalry(ctx context.Context, firstTeamID, secondTeamID int) (*types.Rivalry, error) {
+	return s.rivalryStore.GetRivalry(ctx, firstTeamID, secondTeamID)
+}
+
+// Метод для добавления или изменения дерби
+// Упорядочивает команды и проверяет данные перед сохранением
+func (s *RivalryService) HandleSaveRivalry(ctx context.Context, rivalry types.Rivalry) error {
+	if rivalry.TeamA > rivalry.TeamB {
+		rivalry.TeamA, rivalry.TeamB = rivalry.TeamB, rivalry.TeamA
+		rivalry.TeamAName, rivalry.TeamBName = rivalry.TeamBName, rivalry.TeamAName
+	}
+	if err := rivalry.Validate(); err != nil {
+		return err
+	}
+	rivalry.UpdatedAt = time.Now().UTC()
+	return s.rivalryStore.UpsertRivalry(ctx, rivalry)
+}
+
+// Метод для поиска команды по ID или названию
+// Возвращает nil, если команда не найдена ни в одной коллекции
+func (s *RivalryService) HandleFindTeam(ctx context.Context, query string) (*types.Team, error) {
+	return findTeam(ctx, s.teamsStore, query)
+}
+
+// Ищет команду по очереди во всех коллекциях teamSearchCollections
+func findTeam(ctx context.Context, teamsStore mongoRepo.TeamsSearchStore, query string) (*types.Team, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	for _, coll := range teamSearchCollections {
+		team, err := teamsStore.FindTeam(ctx, coll, query)
+		if err != nil {
+			return nil, fmt.Errorf("error finding team in %s: %w", coll, err)
+		}
+		if team != nil {
+			return team, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/client"
+	mongorepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// SeasonService следит за текущим сезоном каждой лиги: датами начала и конца и текущим туром
+// По нему таблицы и загрузка истории переходят на новый сезон без правки кода
+type SeasonService struct {
+	seasonStore mongorepo.SeasonStore
+	apiClient   client.CompetitionsApiClient
+	now         func() time.Time
+}
+
+// Конструктор для создания нового экземпляра SeasonService
+func NewSeasonService(seasonStore mongorepo.SeasonStore, apiClient client.CompetitionsApiClient) *SeasonService {
+	return &SeasonService{
+		seasonStore: seasonStore,
+		apiClient:   apiClient,
+		now:         time.Now,
+	}
+}
+
+// Метод для обновления сезонов всех лиг из API
+// Ошибка по одной лиге не мешает обновить остальные; возвращаются сохранённые сезоны и все ошибки
+func (s *SeasonService) HandleRefreshSeasons(ctx context.Context) ([]types.Season, error) {
+	var seasons []types.Season
+	var errs []error
+	for leagueName, league := range types.Leagues {
+		season, err := s.apiClient.FetchSeason(ctx, league.Code)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error fetching season of %s: %w", leagueName, err))
+			continue
+		}
+		season.League, season.Code, season.UpdatedAt = leagueName, league.Code, s.now().UTC()
+		if err := s.seasonStore.SaveSeason(ctx, season); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		seasons = append(seasons, season)
+	}
+	return seasons, errors.Join(errs...)
+}
+
+// Метод для получения года сезона, за который сейчас показывается таблица лиги
+func (s *SeasonService) HandleGetActiveSeason(ctx context.Context, code string) (int, error) {
+	season, err := s.seasonStore.GetSeason(ctx, code)
+	if err != nil {
+		return 0, err
+	}
+	return ActiveSeason(season, s.now()), nil
+}
+
+// Метод для получения даты, с которой нужно загрузить историю за seasons последних сезонов
+// Берётся самое раннее начало активного сезона среди лиг и сдвигается на seasons-1 лет назад
+func (s *SeasonService) HandleGetHistoryStart(ctx context.Context, seasons int) (string, error) {
+	stored, err := s.seasonStore.GetSeasons(ctx)
+	if err != nil {
+		return "", err
+	}
+	return HistoryStart(stored, seasons, s.now()), nil
+}
+
+// ActiveSeason возвращает год сезона, который сейчас актуален для лиги
+// Летом API уже может отдавать новый сезон, который ещё не начался: до его старта
+// актуальна итоговая таблица прошлого сезона. После конца сезона он остаётся актуальным,
+// пока API не перейдёт на следующий. Если сезон ещё не загружен, год определяется по дате
+func ActiveSeason(season *types.Season, now time.Time) int {
+	if season == nil || season.Year() == 0 {
+		return SeasonOf(now)
+	}
+	if now.UTC().Format("2006-01-02") < season.StartDate {
+		return season.Year() - 1
+	}
+	return season.Year()
+}
+
+// HistoryStart возвращает дату начала истории за seasons последних сезонов в формате 2006-01-02
+func HistoryStart(stored []types.Season, seasons int, now time.Time) string {
+	if seasons < 1 {
+		seasons = 1
+	}
+	var start time.Time
+	for i := range stored {
+		year := ActiveSeason(&stored[i], now)
+		date, err := time.Parse("2006-01-02", stored[i].StartDate)
+		if err != nil {
+			continue
+		}
+		// Начало прошлого сезона, если текущий ещё не стартовал
+		date = date.AddDate(year-stored[i].Year(), 0, 0)
+		if start.IsZero() || date.Before(start) {
+			start = date
+		}
+	}
+	if start.IsZero() {
+		// Сезоны ещё не загружены: европейские сезоны начинаются в июле
+		start = time.Date(SeasonOf(now), time.July, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return start.AddDate(-(seasons - 1), 0, 0).Format("2006-01-02")
+}
+
+// SeasonOf возвращает год начала сезона по дате: сезоны в Европе начинаются летом,
+// поэтому май 2025 относится к сезону 2024
+func SeasonOf(date time.Time) int {
+	if date.Month() >= time.July {
+		return date.Year()
+	}
+	return date.Year() - 1
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+func TestActiveSeason(t *testing.T) {
+	day := func(date string) time.Time {
+		d, _ := time.Parse("2006-01-02", date)
+		return d
+	}
+	next := &types.Season{Code: "PL", StartDate: "2025-08-15", EndDate: "2026-05-24"}
+	cases := []struct {
+		name   string
+		season *types.Season
+		now    time.Time
+		want   int
+	}{
+		{"no stored season in spring", nil, day("2025-04-01"), 2024},
+		{"no stored season in autumn", nil, day("2025-10-01"), 2025},
+		{"new season announced but not started", next, day("2025-07-20"), 2024},
+		{"new season started", next, day("2025-08-15"), 2025},
+		{"season over, API not rolled over yet", next, day("2026-06-30"), 2025},
+	}
+	for _, c := range cases {
+		if got := ActiveSeason(c.season, c.now); got != c.want {
+			t.Errorf("%s: ActiveSeason = %d, want %d", c.name, got, c.want)
+		}
+	}
+}
+
+func TestHistoryStart(t *testing.T) {
+	now := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	stored := []types.Season{
+		{Code: "PL", StartDate: "2025-08-15"},
+		{Code: "BL1", StartDate: "2025-08-22"},
+		{Code: "CL", StartDate: "2025-07-08"},
+	}
+	if got := HistoryStart(stored, 2, now); got != "2024-07-08" {
+		t.Errorf("HistoryStart = %s, want the earliest active start a season back", got)
+	}
+	// Летом новый сезон ещё не начался, поэтому история считается от прошлого
+	summer := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	if got := HistoryStart(stored[:1], 1, summer); got != "2024-08-15" {
+		t.Errorf("HistoryStart in summer = %s, want 2024-08-15", got)
+	}
+	if got := HistoryStart(nil, 2, now); got != "2024-07-01" {
+		t.Errorf("HistoryStart without seasons = %s, want the calendar fallback", got)
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Коды соревнований по короткому названию, как его сохраняет tools.MatchFilter,
+// для матчей, сохранённых до появления Competition.Code
+var competitionCodesByName = map[string]string{
+	"UCL":        "CL",
+	"UEL":        "EL",
+	"FACup":      "FAC",
+	"DFBPokal":   "DFB",
+	"CopaDelRey": "CDR",
+}
+
+// Ключ веса лиги в профиле для кубковых соревнований
+// Используется, когда лиги команд неизвестны (соперники из лиг, которых нет в базе)
+var competitionLeagueKeys = map[string]string{
+	"CL":  "ChampionsLeague",
+	"EL":  "EuropaLeague",
+	"FAC": "PremierLeague",
+	"DFB": "Bundesliga",
+	"CDR": "LaLiga",
+}
+
+// CompetitionCode возвращает код соревнования матча
+func CompetitionCode(match types.Match) string {
+	if match.Competition.Code != "" {
+		return match.Competition.Code
+	}
+	return competitionCodesByName[match.Competition.Name]
+}
+
+// Множитель бонуса стадии для ответного матча по разнице в первом матче:
+// при равном счёте ответный матч решает всё, при крупном отрыве интрига почти пропадает
+func secondLegFactor(aggregateDiff int) float64 {
+	switch {
+	case aggregateDiff == 0:
+		return 1.2
+	case aggregateDiff == 1:
+		return 1.0
+	case aggregateDiff == 2:
+		return 0.6
+	default:
+		return 0.25
+	}
+}
+
+// GetStageBonus вычисляет бонус за стадию кубкового турнира по таблице профиля
+// Для ответных матчей учитывает счёт первого матча и возвращает его в виде "2:1"
+// с точки зрения хозяев текущего матча; для остальных матчей aggregate пустой
+func GetStageBonus(ctx context.Context, calculator Calculator, match types.Match, profile types.RatingProfile) (bonus float64, aggregate string) {
+	weight := profile.StageWeights[CompetitionCode(match)][match.Stage]
+	if weight == 0 {
+		return 0, ""
+	}
+
+	firstLeg, err := calculator.HandleGetFirstLeg(ctx, match)
+	if err != nil {
+		log.Printf("Error getting first leg for match %d: %v", match.ID, err)
+		return weight, ""
+	}
+	if firstLeg == nil {
+		return weight, ""
+	}
+
+	// В первом матче хозяева текущего были гостями
+	scored, conceded := firstLeg.Score.FullTime.Away, firstLeg.Score.FullTime.Home
+	diff := scored - conceded
+	if diff < 0 {
+		diff = -diff
+	}
+	return math.Min(1, weight*secondLegFactor(diff)), fmt.Sprintf("%d:%d", scored, conceded)
+}
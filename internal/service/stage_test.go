@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/tools"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Калькулятор, который знает только первый матч противостояния
+type firstLegCalculator struct {
+	Calculator
+	firstLeg *types.Match
+}
+
+func (c firstLegCalculator) HandleGetFirstLeg(ctx context.Context, match types.Match) (*types.Match, error) {
+	return c.firstLeg, nil
+}
+
+func TestGetStageBonus(t *testing.T) {
+	profile := types.DefaultRatingProfile()
+	var match types.Match
+	match.Competition.Name = "UCL"
+	match.Stage = "QUARTER_FINALS"
+
+	single, aggregate := GetStageBonus(context.Background(), firstLegCalculator{}, match, profile)
+	if single != profile.StageWeights["CL"]["QUARTER_FINALS"] || aggregate != "" {
+		t.Errorf("first leg: got %.2f %q", single, aggregate)
+	}
+
+	var level, rout types.Match
+	level.Score.FullTime.Home, level.Score.FullTime.Away = 1, 1
+	rout.Score.FullTime.Home, rout.Score.FullTime.Away = 4, 0
+
+	levelBonus, aggregate := GetStageBonus(context.Background(), firstLegCalculator{firstLeg: &level}, match, profile)
+	if aggregate != "1:1" || levelBonus <= single {
+		t.Errorf("level tie: got %.2f %q, want more than %.2f", levelBonus, aggregate, single)
+	}
+	routBonus, aggregate := GetStageBonus(context.Background(), firstLegCalculator{firstLeg: &rout}, match, profile)
+	if aggregate != "0:4" || routBonus >= single {
+		t.Errorf("decided tie: got %.2f %q, want less than %.2f", routBonus, aggregate, single)
+	}
+
+	match.Stage = "LEAGUE_STAGE"
+	if bonus, _ := GetStageBonus(context.Background(), firstLegCalculator{}, match, profile); bonus != 0 {
+		t.Errorf("league stage should have no bonus, got %.2f", bonus)
+	}
+}
+
+func TestCompetitionCodeOfStoredCups(t *testing.T) {
+	// Матчи без кода, как их сохранял MatchFilter до появления Competition.Code
+	names := map[string]string{
+		"UEFA Champions League": "CL",
+		"UEFA Europa League":    "EL",
+		"FA Cup":                "FAC",
+		"DFB-Pokal":             "DFB",
+		"Copa del Rey":          "CDR",
+	}
+	for name, code := range names {
+		var m types.Match
+		m.Competition.Name = name
+		stored := tools.MatchFilter(types.MatchesResponse{Matches: []types.Match{m}})
+		if len(stored) != 1 {
+			t.Errorf("%s: MatchFilter dropped the match", name)
+			continue
+		}
+		if got := CompetitionCode(stored[0]); got != code {
+			t.Errorf("%s: CompetitionCode = %q, want %q", name, got, code)
+		}
+		if len(types.DefaultRatingProfile().StageWeights[code]) == 0 {
+			t.Errorf("%s: no stage weights for %s", name, code)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Стадия football-data для матчей регулярного чемпионата
+const regularSeasonStage = "REGULAR_SEASON"
+
+// Относительная важность гонок: борьба за титул важнее борьбы за еврокубки
+const (
+	titleRaceWeight         = 1.0
+	qualificationRaceWeight = 0.7
+	relegationRaceWeight    = 0.9
+)
+
+// Прямые соперники по одной гонке получают надбавку к значимости ("матч за шесть очков")
+const directRivalsFactor = 1.25
+
+// Гонка в турнирной таблице: места 1..Cutoff выше черты, остальные - ниже
+type race struct {
+	cutoff int
+	weight float64
+}
+
+// CalculateStakes оценивает турнирную значимость матча от 0 до 1
+// Для каждой гонки (титул, еврокубки, выживание) считается, насколько каждая команда
+// ещё может пересечь черту при оставшихся матчах, с учётом прогресса сезона.
+// Итог - значимость самой важной гонки; если обе команды в одной гонке, она выше
+func CalculateStakes(standings []types.Standing, league string, homeID, awayID int) float64 {
+	teams := types.TeamsInLeague[league]
+	zones, ok := types.LeagueZones[league]
+	if !ok || teams < 2 || len(standings) == 0 {
+		return 0
+	}
+	byPosition := make(map[int]types.Standing, len(standings))
+	var home, away *types.Standing
+	for i := range standings {
+		byPosition[standings[i].Position] = standings[i]
+		switch standings[i].Team.ID {
+		case homeID:
+			home = &standings[i]
+		case awayID:
+			away = &standings[i]
+		}
+	}
+	if home == nil || away == nil {
+		return 0
+	}
+
+	games := 2 * (teams - 1)
+	races := []race{
+		{cutoff: 1, weight: titleRaceWeight},
+		{cutoff: zones.Qualification, weight: qualificationRaceWeight},
+		{cutoff: teams - zones.Relegation, weight: relegationRaceWeight},
+	}
+
+	var stakes float64
+	for _, r := range races {
+		homeStake := raceStake(*home, r.cutoff, byPosition, games)
+		awayStake := raceStake(*away, r.cutoff, byPosition, games)
+		s := (homeStake + awayStake) / 2
+		if homeStake > 0 && awayStake > 0 {
+			s = math.Min(1, math.Max(homeStake, awayStake)*directRivalsFactor)
+		}
+		stakes = math.Max(stakes, s*r.weight)
+	}
+	return stakes
+}
+
+// Значимость гонки для одной команды от 0 до 1
+// Считается по отрыву от черты (для команды выше черты - от первой команды под ней)
+// относительно очков, которые ещё можно набрать, и растёт к концу сезона
+func raceStake(team types.Standing, cutoff int, byPosition map[int]types.Standing, games int) float64 {
+	remaining := games - team.PlayedGames
+	if remaining <= 0 {
+		return 0
+	}
+	var gap int
+	if team.Position <= cutoff {
+		below, ok := byPosition[cutoff+1]
+		if !ok {
+			return 0
+		}
+		gap = team.Points - below.Points
+	} else {
+		above, ok := byPosition[cutoff]
+		if !ok {
+			return 0
+		}
+		gap = above.Points - team.Points
+	}
+	reachable := float64(3 * remaining)
+	if float64(gap) > reachable {
+		return 0
+	}
+	closeness := 1 - float64(gap)/reachable
+	progress := float64(team.PlayedGames) / float64(games)
+	return closeness * progress
+}
+
+// GetStakes оценивает турнирную значимость матча по сохранённой таблице
+// Возвращает 0 для матчей не регулярного чемпионата и для команд из разных лиг
+func GetStakes(ctx context.Context, calculator Calculator, match types.Match, homeLeague, awayLeague string) (float64, error) {
+	if homeLeague != awayLeague || (match.Stage != "" && match.Stage != regularSeasonStage) {
+		return 0, nil
+	}
+	if _, ok := types.LeagueZones[homeLeague]; !ok {
+		return 0, nil
+	}
+	standings, err := calculator.HandleGetStandings(ctx, homeLeague)
+	if err != nil {
+		return 0, fmt.Errorf("error getting standings for %s: %w", homeLeague, err)
+	}
+	return CalculateStakes(standings, homeLeague, match.HomeTeam.ID, match.AwayTeam.ID), nil
+}
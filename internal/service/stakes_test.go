@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// Таблица из 20 команд после played туров: у команды на месте i очки points[i-1]
+func stakesTable(played int, points []int) []types.Standing {
+	standings := make([]types.Standing, len(points))
+	for i, p := range points {
+		standings[i] = types.Standing{Position: i + 1, PlayedGames: played, Points: p}
+		standings[i].Team.ID = i + 1
+	}
+	return standings
+}
+
+func TestCalculateStakes(t *testing.T) {
+	late := stakesTable(35, []int{80, 79, 70, 66, 64, 60, 55, 52, 50, 49, 48, 47, 46, 45, 40, 36, 35, 34, 25, 20})
+
+	titleDecider := CalculateStakes(late, "PremierLeague", 1, 2)
+	sixPointer := CalculateStakes(late, "PremierLeague", 16, 18)
+	midTable := CalculateStakes(late, "PremierLeague", 9, 12)
+
+	if titleDecider <= midTable || sixPointer <= midTable {
+		t.Errorf("races should matter more than mid-table: title %.2f, relegation %.2f, mid-table %.2f", titleDecider, sixPointer, midTable)
+	}
+	if titleDecider > 1 || sixPointer > 1 {
+		t.Errorf("stakes must not exceed 1: title %.2f, relegation %.2f", titleDecider, sixPointer)
+	}
+
+	early := stakesTable(3, []int{9, 9, 7, 7, 6, 6, 5, 5, 4, 4, 4, 3, 3, 3, 2, 2, 1, 1, 0, 0})
+	if got := CalculateStakes(early, "PremierLeague", 1, 2); got >= titleDecider {
+		t.Errorf("an early-season top game should matter less than a late title decider: %.2f >= %.2f", got, titleDecider)
+	}
+
+	if got := CalculateStakes(late, "ChampionsLeague", 1, 2); got != 0 {
+		t.Errorf("leagues without zones should have no stakes, got %.2f", got)
+	}
+}
@@ -31,10 +31,32 @@ func (s *StandingsService) HandleGetStandings(ctx context.Context, league string
 }
 
 // Метод для сохранения таблиц лиг в базу MongoDB
-func (s *StandingsService) HandleSaveStandings(ctx context.Context, league string, standings []types.Standing) error {
-	err := s.standingsStore.SaveStandings(ctx, league, standings)
+// Возвращает ID команд, чьё место, очки или число матчей изменились по сравнению с прошлой таблицей:
+// рейтинги их будущих матчей нужно пересчитать
+func (s *StandingsService) HandleSaveStandings(ctx context.Context, league string, standings []types.Standing) ([]int, error) {
+	previous, err := s.standingsStore.GetStandings(ctx, league)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := s.standingsStore.SaveStandings(ctx, league, standings); err != nil {
+		return nil, err
+	}
+	return ChangedStandings(previous, standings), nil
+}
+
+// ChangedStandings возвращает ID команд, строки которых различаются в двух таблицах
+// Команды, которых нет в прошлой таблице, тоже считаются изменившимися
+func ChangedStandings(previous, current []types.Standing) []int {
+	rows := make(map[int]types.Standing, len(previous))
+	for _, s := range previous {
+		rows[s.Team.ID] = s
+	}
+	var changed []int
+	for _, s := range current {
+		old, ok := rows[s.Team.ID]
+		if !ok || old.Position != s.Position || old.Points != s.Points || old.PlayedGames != s.PlayedGames {
+			changed = append(changed, s.Team.ID)
+		}
 	}
-	return nil
+	return changed
 }
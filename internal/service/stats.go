@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	mongoRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+const (
+	// За сколько дней берутся результаты для модели Пуассона
+	statsHistoryDays = 730
+	// Как часто модель переоценивается по свежим результатам
+	statsRefitInterval = time.Hour
+	// На сколько дней вперёд ищется ближайший матч команды
+	statsUpcomingDays = 14
+)
+
+// StatsService строит прогнозы матчей по модели Пуассона и считает зрелищность команд
+// Модель оценивается по сыгранным матчам и держится в памяти до следующей переоценки
+type StatsService struct {
+	matchesStore mongoRepo.StatsMatchesStore
+	teamsStore   mongoRepo.TeamsSearchStore
+	now          func() time.Time
+
+	mu       sync.Mutex
+	model    *PoissonModel
+	fittedAt time.Time
+}
+
+// Конструктор для создания нового экземпляра StatsService
+func NewStatsService(matchesStore mongoRepo.StatsMatchesStore, teamsStore mongoRepo.TeamsSearchStore) *StatsService {
+	return &StatsService{
+		matchesStore: matchesStore,
+		teamsStore:   teamsStore,
+		now:          time.Now,
+	}
+}
+
+// Метод для получения прогноза матча
+// Возвращает nil, если ни об одной из команд нет сыгранных матчей
+func (s *StatsService) HandleGetPrediction(ctx context.Context, match types.Match) (*types.Prediction, error) {
+	model, err := s.currentModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prediction, ok := model.Predict(match.HomeTeam.ID, match.AwayTeam.ID)
+	if !ok {
+		return nil, nil
+	}
+	return prediction, nil
+}
+
+// Метод для получения зрелищности команды по её последним матчам
+func (s *StatsService) HandleGetEntertainment(ctx context.Context, teamID int) (types.Entertainment, error) {
+	matches, err := s.matchesStore.GetRecentMatches(ctx, teamID, EntertainmentMatches)
+	if err != nil {
+		return types.Entertainment{}, fmt.Errorf("error getting recent matches: %w", err)
+	}
+	return TeamEntertainment(teamID, matches), nil
+}
+
+// Метод для получения ближайшего несыгранного матча команды
+// Возвращает nil, если команда не найдена или в ближайшие дни у неё нет матчей
+func (s *StatsService) HandleGetNextMatch(ctx context.Context, query string) (*types.Match, error) {
+	team, err := findTeam(ctx, s.teamsStore, query)
+	if err != nil || team == nil {
+		return nil, err
+	}
+	from := s.now().UTC()
+	to := from.AddDate(0, 0, statsUpcomingDays)
+	matches, err := s.matchesStore.GetMatchesInPeriod(ctx, "", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("error getting upcoming matches: %w", err)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].UTCDate < matches[j].UTCDate })
+	now := from.Format(time.RFC3339)
+	for _, m := range matches {
+		if (m.HomeTeam.ID == team.ID || m.AwayTeam.ID == team.ID) && m.Status != "FINISHED" && m.UTCDate >= now {
+			return &m, nil
+		}
+	}
+	return nil, nil
+}
+
+// Возвращает модель, переоценивая её не чаще statsRefitInterval
+func (s *StatsService) currentModel(ctx context.Context) (*PoissonModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.model != nil && now.Sub(s.fittedAt) < statsRefitInterval {
+		return s.model, nil
+	}
+	since := now.AddDate(0, 0, -statsHistoryDays).UTC().Format(time.RFC3339)
+	matches, err := s.matchesStore.GetFinishedMatches(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("error getting finished matches: %w", err)
+	}
+	s.model = FitPoisson(matches, now)
+	s.fittedAt = now
+	return s.model, nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	mongoRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/mongodb"
+	pgRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/postgres"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+const (
+	// С какой неожиданности результат считается сенсацией: у исхода было не больше 25% шансов
+	upsetMinSurprise = 0.75
+	// За какой период ищутся сенсации для уведомлений; результаты попадают в базу раз в сутки
+	upsetAlertWindow = 48 * time.Hour
+	// За сколько дней собираются главные сенсации недели
+	upsetShocksDays = 7
+)
+
+// UpsetService ищет сенсации среди сыгранных матчей и рассылает уведомления подписчикам лиг
+// Ожидание перед матчем берётся из модели Пуассона, оценённой по матчам до дня игры;
+// если по командам нет истории - из мест в текущей таблице лиги
+type UpsetService struct {
+	matchesStore   mongoRepo.StatsMatchesStore
+	standingsStore mongoRepo.StandingsStore
+	alertStore     pgRepo.UpsetAlertStore
+	prefStore      pgRepo.PreferenceStore
+	now            func() time.Time
+}
+
+// Конструктор для создания нового экземпляра UpsetService
+func NewUpsetService(matchesStore mongoRepo.StatsMatchesStore, standingsStore mongoRepo.StandingsStore, alertStore pgRepo.UpsetAlertStore, prefStore pgRepo.PreferenceStore) *UpsetService {
+	return &UpsetService{
+		matchesStore:   matchesStore,
+		standingsStore: standingsStore,
+		alertStore:     alertStore,
+		prefStore:      prefStore,
+		now:            time.Now,
+	}
+}
+
+// Метод для получения сенсаций среди матчей, сыгранных с from по to, самые неожиданные первыми
+func (s *UpsetService) HandleGetUpsets(ctx context.Context, from, to time.Time) ([]types.Upset, error) {
+	since := from.AddDate(0, 0, -statsHistoryDays).UTC().Format(time.RFC3339)
+	history, err := s.matchesStore.GetFinishedMatches(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("error getting finished matches: %w", err)
+	}
+	start, end := from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339)
+	models := make(map[string]*PoissonModel)
+	positions := make(map[string]map[int]int)
+
+	var upsets []types.Upset
+	for _, match := range history {
+		if match.UTCDate < start || match.UTCDate > end {
+			continue
+		}
+		// Модель оценивается на начало дня матча, поэтому его результат в ней не учтён
+		day := match.UTCDate[:10]
+		model, ok := models[day]
+		if !ok {
+			dayStart, err := time.Parse("2006-01-02", day)
+			if err != nil {
+				continue
+			}
+			model = FitPoisson(history, dayStart)
+			models[day] = model
+		}
+		prediction, _ := model.Predict(match.HomeTeam.ID, match.AwayTeam.ID)
+
+		var homePos, awayPos, size int
+		if league, ok := domesticLeague(match); ok {
+			table, ok := positions[league]
+			if !ok {
+				table, err = s.tablePositions(ctx, league)
+				if err != nil {
+					return nil, err
+				}
+				positions[league] = table
+			}
+			homePos, awayPos, size = table[match.HomeTeam.ID], table[match.AwayTeam.ID], types.TeamsInLeague[league]
+		}
+		if upset, ok := DetectUpset(match, prediction, homePos, awayPos, size); ok {
+			upsets = append(upsets, upset)
+		}
+	}
+	sort.SliceStable(upsets, func(i, j int) bool {
+		return upsets[i].Surprise > upsets[j].Surprise
+	})
+	return upsets, nil
+}
+
+// Метод для получения главных сенсаций последней недели
+func (s *UpsetService) HandleGetWeeklyShocks(ctx context.Context, limit int) ([]types.Upset, error) {
+	now := s.now().UTC()
+	upsets, err := s.HandleGetUpsets(ctx, now.AddDate(0, 0, -upsetShocksDays), now)
+	if err != nil {
+		return nil, err
+	}
+	if len(upsets) > limit {
+		upsets = upsets[:limit]
+	}
+	return upsets, nil
+}
+
+// Метод для получения ещё не разосланных уведомлений о сенсациях
+// Сенсация отмечается разосланной только после того, как получатели загружены, и сразу,
+// даже если на лигу никто не подписан, чтобы новые подписчики не получали уведомления о старых матчах
+// Если получателей загрузить не удалось, сенсация останется неразосланной до следующего запуска
+func (s *UpsetService) HandlePendingAlerts(ctx context.Context) ([]types.UpsetAlert, error) {
+	now := s.now().UTC()
+	upsets, err := s.HandleGetUpsets(ctx, now.Add(-upsetAlertWindow), now)
+	if err != nil {
+		return nil, err
+	}
+	var alerts []types.UpsetAlert
+	followersByLeague := make(map[string][]int64)
+	for _, upset := range upsets {
+		league := upset.Match.Competition.Name
+		followers, ok := followersByLeague[league]
+		if !ok {
+			followers, err = s.prefStore.GetFollowers(ctx, types.PreferenceLeague, league)
+			if err != nil {
+				return alerts, fmt.Errorf("error getting followers of %s: %w", league, err)
+			}
+			followersByLeague[league] = followers
+		}
+		fresh, err := s.alertStore.MarkUpsetAlerted(ctx, upset.Match.ID, upset.Surprise)
+		if err != nil {
+			return alerts, fmt.Errorf("error marking upset %d: %w", upset.Match.ID, err)
+		}
+		if fresh && len(followers) > 0 {
+			alerts = append(alerts, types.UpsetAlert{Upset: upset, Recipients: followers})
+		}
+	}
+	return alerts, nil
+}
+
+// Места команд в текущей таблице лиги
+func (s *UpsetService) tablePositions(ctx context.Context, league string) (map[int]int, error) {
+	standings, err := s.standingsStore.GetStandings(ctx, types.Leagues[league].CollectionName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting standings for %s: %w", league, err)
+	}
+	positions := make(map[int]int, len(standings))
+	for _, st := range standings {
+		positions[st.Team.ID] = st.Position
+	}
+	return positions, nil
+}
+
+// DetectUpset сравнивает результат матча с ожиданием и решает, была ли это сенсация
+// Если есть прогноз, неожиданность равна 1 - вероятность случившегося исхода.
+// Иначе учитывается только победа команды, стоявшей ниже в таблице: чем больше разрыв
+// в местах относительно размера лиги, тем больше неожиданность
+func DetectUpset(match types.Match, prediction *types.Prediction, homePos, awayPos, leagueSize int) (types.Upset, bool) {
+	hg, ag := match.Score.FullTime.Home, match.Score.FullTime.Away
+	upset := types.Upset{Match: match, HomePosition: homePos, AwayPosition: awayPos}
+	if prediction != nil {
+		p := prediction.Draw
+		switch {
+		case hg > ag:
+			p = prediction.HomeWin
+		case hg < ag:
+			p = prediction.AwayWin
+		}
+		upset.Basis = types.UpsetBasisModel
+		upset.ResultProbability = p
+		upset.Surprise = 1 - p
+		return upset, upset.Surprise >= upsetMinSurprise
+	}
+	if homePos <= 0 || awayPos <= 0 || leagueSize <= 0 || hg == ag {
+		return upset, false
+	}
+	gap := homePos - awayPos
+	if hg < ag {
+		gap = -gap
+	}
+	if gap <= 0 {
+		return upset, false
+	}
+	upset.Basis = types.UpsetBasisTable
+	upset.ResultProbability = 0.5 * (1 - float64(gap)/float64(leagueSize))
+	upset.Surprise = 1 - upset.ResultProbability
+	return upset, upset.Surprise >= upsetMinSurprise
+}
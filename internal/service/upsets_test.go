@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+func TestDetectUpsetByModel(t *testing.T) {
+	prediction := &types.Prediction{HomeWin: 0.7, Draw: 0.2, AwayWin: 0.1}
+
+	upset, ok := DetectUpset(playedMatch(1, "2025-03-01T15:00:00Z", 1, 2, 0, 1), prediction, 0, 0, 0)
+	if !ok || upset.Basis != types.UpsetBasisModel {
+		t.Fatalf("an away win at 10%% should be an upset, got %+v", upset)
+	}
+	if upset.ResultProbability != 0.1 || upset.Surprise != 0.9 {
+		t.Errorf("unexpected surprise %+v", upset)
+	}
+	if _, ok := DetectUpset(playedMatch(2, "2025-03-01T15:00:00Z", 1, 2, 2, 0), prediction, 0, 0, 0); ok {
+		t.Error("the favourite winning is not an upset")
+	}
+	likelyDraw := &types.Prediction{HomeWin: 0.6, Draw: 0.3, AwayWin: 0.1}
+	if _, ok := DetectUpset(playedMatch(3, "2025-03-01T15:00:00Z", 1, 2, 1, 1), likelyDraw, 0, 0, 0); ok {
+		t.Error("a draw at 30% is below the surprise threshold")
+	}
+}
+
+func TestDetectUpsetByTable(t *testing.T) {
+	// Без прогноза: 18-е место обыгрывает 2-е в лиге из 20 команд
+	upset, ok := DetectUpset(playedMatch(1, "2025-03-01T15:00:00Z", 1, 2, 2, 1), nil, 18, 2, 20)
+	if !ok || upset.Basis != types.UpsetBasisTable || upset.Surprise != 0.9 {
+		t.Errorf("a bottom side beating the runner-up should be an upset, got %+v, %v", upset, ok)
+	}
+	if _, ok := DetectUpset(playedMatch(2, "2025-03-01T15:00:00Z", 1, 2, 0, 1), nil, 18, 2, 20); ok {
+		t.Error("the higher-placed team winning is not an upset")
+	}
+	if _, ok := DetectUpset(playedMatch(3, "2025-03-01T15:00:00Z", 1, 2, 1, 0), nil, 8, 5, 20); ok {
+		t.Error("a small gap in the table should not count")
+	}
+	if _, ok := DetectUpset(playedMatch(4, "2025-03-01T15:00:00Z", 1, 2, 1, 0), nil, 0, 5, 20); ok {
+		t.Error("a team without a position gives no table expectation")
+	}
+}
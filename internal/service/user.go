@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 
 	userRepo "github.com/vsespontanno/tgbot_fschedule/internal/repository/postgres"
 	"github.com/vsespontanno/tgbot_fschedule/internal/types"
@@ -12,12 +15,17 @@ import (
 // Реализует интерфейс UserStore для взаимодействия с данными пользователей
 type UserService struct {
 	userStore userRepo.UserStore
+	// Все хранилища с персональными данными; userStore всегда последний,
+	// чтобы при удалении сначала чистились зависимые таблицы
+	dataStores []userRepo.PersonalDataStore
 }
 
 // Конструктор для создания нового экземпляра UserService
-func NewUserService(userStore userRepo.UserStore) *UserService {
+// dataStores - дополнительные хранилища с данными пользователя (подписки, прогнозы и т.д.)
+func NewUserService(userStore userRepo.UserStore, dataStores ...userRepo.PersonalDataStore) *UserService {
 	return &UserService{
-		userStore: userStore,
+		userStore:  userStore,
+		dataStores: append(dataStores, userStore),
 	}
 }
 
@@ -32,3 +40,31 @@ func (s *UserService) GetUserByTelegramID(ctx context.Context, telegramID int64)
 func (s *UserService) SaveUser(ctx context.Context, user *types.User) error {
 	return s.userStore.SaveUser(ctx, user)
 }
+
+// Метод для выгрузки всех данных пользователя в JSON
+// Обходит все зарегистрированные хранилища и собирает их разделы в один документ
+func (s *UserService) ExportUserData(ctx context.Context, telegramID int64) ([]byte, error) {
+	export := map[string]interface{}{
+		"telegram_id": telegramID,
+		"exported_at": time.Now().UTC(),
+	}
+	for _, store := range s.dataStores {
+		data, err := store.ExportUserData(ctx, telegramID)
+		if err != nil {
+			return nil, fmt.Errorf("error exporting %s: %w", store.Section(), err)
+		}
+		export[store.Section()] = data
+	}
+	return json.MarshalIndent(export, "", "  ")
+}
+
+// Метод для удаления всех данных пользователя
+// Хранилища обходятся по порядку, таблица users удаляется последней
+func (s *UserService) DeleteUserData(ctx context.Context, telegramID int64) error {
+	for _, store := range s.dataStores {
+		if err := store.DeleteUserData(ctx, telegramID); err != nil {
+			return fmt.Errorf("error deleting %s: %w", store.Section(), err)
+		}
+	}
+	return nil
+}
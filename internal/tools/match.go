@@ -49,6 +49,12 @@ func MatchFilter(MatchesResponse types.MatchesResponse) []types.Match {
 			MatchesResponse.Matches[i].Competition.Name = "UCL"
 		case "UEFA Europa League":
 			MatchesResponse.Matches[i].Competition.Name = "UEL"
+		case "FA Cup":
+			MatchesResponse.Matches[i].Competition.Name = "FACup"
+		case "DFB-Pokal":
+			MatchesResponse.Matches[i].Competition.Name = "DFBPokal"
+		case "Copa del Rey":
+			MatchesResponse.Matches[i].Competition.Name = "CopaDelRey"
 		case "Primera Division":
 			MatchesResponse.Matches[i].Competition.Name = "LaLiga"
 		case "Primeira Liga":
@@ -66,7 +72,7 @@ func MatchFilter(MatchesResponse types.MatchesResponse) []types.Match {
 		}
 	}
 
-	// Фильтруем матчи только нужных лиг
+	// Фильтруем матчи только нужных лиг и кубков
 	var filteredMatches []types.Match
 	allowedLeagues := map[string]bool{
 		"LaLiga":     true,
@@ -75,6 +81,10 @@ func MatchFilter(MatchesResponse types.MatchesResponse) []types.Match {
 		"SerieA":     true,
 		"Ligue1":     true,
 		"UCL":        true,
+		"UEL":        true,
+		"FACup":      true,
+		"DFBPokal":   true,
+		"CopaDelRey": true,
 	}
 	for _, match := range MatchesResponse.Matches {
 		if allowedLeagues[match.Competition.Name] {
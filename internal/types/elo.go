@@ -0,0 +1,34 @@
+package types
+
+import "time"
+
+// Структура для хранения рейтинга Эло команды и истории его изменений
+type TeamElo struct {
+	TeamID   int        `json:"teamId" bson:"teamid"`
+	TeamName string     `json:"teamName" bson:"teamname"`
+	Rating   float64    `json:"rating" bson:"rating"`
+	Matches  int        `json:"matches" bson:"matches"`
+	History  []EloPoint `json:"history" bson:"history"`
+}
+
+// Структура для точки на графике рейтинга Эло: рейтинг после конкретного матча
+type EloPoint struct {
+	MatchID int     `json:"matchId" bson:"matchid"`
+	Date    string  `json:"date" bson:"date"`
+	Rating  float64 `json:"rating" bson:"rating"`
+	Delta   float64 `json:"delta" bson:"delta"`
+}
+
+// Структура для хранения прогресса пересчёта Эло
+// LastMatchDate - дата самого позднего учтённого матча. ProcessedIDs - учтённые матчи
+// с датой не раньше WindowStart: окно пересматривается при каждом запуске, чтобы не потерять
+// матчи, статус которых пришёл с опозданием
+// LastMatchID остался от состояния без окна (пустой WindowStart): тогда учтёнными считаются
+// матчи до (LastMatchDate, LastMatchID) включительно
+type EloState struct {
+	LastMatchDate string    `json:"lastMatchDate" bson:"lastmatchdate"`
+	LastMatchID   int       `json:"lastMatchId" bson:"lastmatchid"`
+	WindowStart   string    `json:"windowStart" bson:"windowstart"`
+	ProcessedIDs  []int     `json:"processedIds" bson:"processedids"`
+	UpdatedAt     time.Time `json:"updatedAt" bson:"updatedat"`
+}
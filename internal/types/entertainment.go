@@ -0,0 +1,16 @@
+package types
+
+// Структура для хранения зрелищности команды по её последним сыгранным матчам
+// Index от 0 до 1; 0.5 - средняя зрелищность, к ней же тянутся команды с короткой историей
+type Entertainment struct {
+	TeamID  int `json:"teamId"`
+	Matches int `json:"matches"`
+	// Голов за матч с обеих сторон
+	GoalsPerGame float64 `json:"goalsPerGame"`
+	// Доля матчей, в которых забили обе команды
+	BothScoredRate float64 `json:"bothScoredRate"`
+	// Доля матчей, исход которых решился во втором тайме, среди матчей со счётом первого тайма
+	LateSwingRate    float64 `json:"lateSwingRate"`
+	LateSwingMatches int     `json:"lateSwingMatches"`
+	Index            float64 `json:"index"`
+}
@@ -0,0 +1,54 @@
+package types
+
+import "time"
+
+// Виды событий аналитики
+const (
+	EventCommand     = "command"
+	EventCallback    = "callback"
+	EventInlineQuery = "inline_query"
+)
+
+// Исходы обработки события
+const (
+	OutcomeOK        = "ok"
+	OutcomeError     = "error"
+	OutcomeUnhandled = "unhandled"
+)
+
+// Структура для хранения одного события использования бота
+// Name - команда или действие кнопки, League - лига, если она была выбрана
+type Event struct {
+	TelegramID int64     `json:"telegram_id"`
+	ChatType   string    `json:"chat_type"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	League     string    `json:"league,omitempty"`
+	LatencyMs  int64     `json:"latency_ms"`
+	Outcome    string    `json:"outcome"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Структура для счётчика в отчёте (команда или лига и количество запросов)
+type UsageCount struct {
+	Name  string
+	Count int
+}
+
+// Структура для времени ответа по действию
+type LatencyStat struct {
+	Name  string
+	Count int
+	P95Ms float64
+}
+
+// Структура для агрегированного отчёта об использовании бота за день
+type UsageReport struct {
+	Day         time.Time
+	Events      int
+	Users       int
+	Errors      int
+	TopCommands []UsageCount
+	TopLeagues  []UsageCount
+	Latency     []LatencyStat
+}
@@ -0,0 +1,15 @@
+package types
+
+// Структура для хранения формы команды по последним сыгранным матчам
+type Form struct {
+	// Сколько завершённых матчей учтено
+	Played int `json:"played" bson:"played"`
+	// Очки за матч: победа - 3, ничья - 1
+	PointsPerGame float64 `json:"pointsPerGame" bson:"pointspergame"`
+	// Средняя разница мячей за матч
+	GoalDiffPerGame float64 `json:"goalDiffPerGame" bson:"goaldiffpergame"`
+	// Итоговая оценка формы от 0 до 1 с большим весом у последних матчей
+	Rating float64 `json:"rating" bson:"rating"`
+	// Результаты последних матчей буквами W/D/L, самый свежий - последний, например "WWDLW"
+	LastFive string `json:"lastFive" bson:"lastfive"`
+}
@@ -6,6 +6,8 @@ type Match struct {
 	Competition struct {
 		ID   int    `json:"id"`
 		Name string `json:"name"`
+		// Код соревнования football-data, например "CL"; пустой у матчей, сохранённых до его появления
+		Code string `json:"code"`
 	} `json:"competition"`
 	Stage    string `json:"stage"`
 	HomeTeam struct {
@@ -24,8 +26,43 @@ type Match struct {
 			Home int `json:"home"`
 			Away int `json:"away"`
 		} `json:"fullTime"`
+		// Счёт после первого тайма; nil у матчей, сохранённых до его появления
+		HalfTime *HalfTimeScore `json:"halfTime"`
 	} `json:"score"`
 	Rating float64 `json:"rating"`
+	// Стратегия и её версия, которыми посчитан Rating
+	RatingStrategy string `json:"ratingStrategy,omitempty"`
+	RatingVersion  string `json:"ratingVersion,omitempty"`
+	// Разбор рейтинга; пустой у матчей, посчитанных до появления разбора
+	RatingBreakdown *RatingBreakdown `json:"ratingBreakdown,omitempty" bson:"ratingbreakdown,omitempty"`
+}
+
+// Счёт после первого тайма
+type HalfTimeScore struct {
+	Home int `json:"home"`
+	Away int `json:"away"`
+}
+
+// Стратегия, которой считались рейтинги до появления выбора стратегий
+const LegacyRatingStrategy = "heuristic"
+
+// RatedBy возвращает название стратегии, которой посчитан рейтинг матча
+// Матчи без сохранённой стратегии считаются посчитанными LegacyRatingStrategy
+func (m *Match) RatedBy() string {
+	if m.RatingStrategy == "" {
+		return LegacyRatingStrategy
+	}
+	return m.RatingStrategy
+}
+
+// Started сообщает, начался ли матч: идёт, прерван или уже сыгран
+// Несыгранные, перенесённые и отменённые матчи считаются не начавшимися
+func (m *Match) Started() bool {
+	switch m.Status {
+	case "IN_PLAY", "PAUSED", "LIVE", "SUSPENDED", "FINISHED", "AWARDED":
+		return true
+	}
+	return false
 }
 
 // Cтруктура для декодинга Json-файла из API
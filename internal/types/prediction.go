@@ -0,0 +1,23 @@
+package types
+
+import "fmt"
+
+// Структура для хранения прогноза матча по модели Пуассона
+// Вероятности исходов считаются с точки зрения хозяев; HomeGoals и AwayGoals - самый вероятный счёт
+type Prediction struct {
+	HomeExpected     float64 `json:"homeExpected" bson:"homeexpected"`
+	AwayExpected     float64 `json:"awayExpected" bson:"awayexpected"`
+	HomeWin          float64 `json:"homeWin" bson:"homewin"`
+	Draw             float64 `json:"draw" bson:"draw"`
+	AwayWin          float64 `json:"awayWin" bson:"awaywin"`
+	HomeGoals        int     `json:"homeGoals" bson:"homegoals"`
+	AwayGoals        int     `json:"awayGoals" bson:"awaygoals"`
+	ScoreProbability float64 `json:"scoreProbability" bson:"scoreprobability"`
+	// Насколько равны шансы: 1 - победы хозяев и гостей равновероятны, 0 - исход предрешён
+	Closeness float64 `json:"closeness" bson:"closeness"`
+}
+
+// Score возвращает самый вероятный счёт, например "2:1"
+func (p Prediction) Score() string {
+	return fmt.Sprintf("%d:%d", p.HomeGoals, p.AwayGoals)
+}
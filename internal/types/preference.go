@@ -0,0 +1,34 @@
+package types
+
+import "time"
+
+// Виды объектов, на которые можно подписаться или скрыть
+const (
+	PreferenceTeam   = "team"
+	PreferenceLeague = "league"
+)
+
+// Режимы предпочтения: подписка поднимает матчи в топе, скрытие убирает их совсем
+const (
+	PreferenceFollow = "follow"
+	PreferenceHide   = "hide"
+)
+
+// Структура для хранения одного предпочтения пользователя
+// Value - ID команды или название соревнования (как в Match.Competition.Name), Label - название для показа
+type Preference struct {
+	TelegramID int64     `json:"telegram_id"`
+	Kind       string    `json:"kind"`
+	Value      string    `json:"value"`
+	Label      string    `json:"label"`
+	Mode       string    `json:"mode"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Структура для хранения матча из персонального топа
+// Relevance - рейтинг матча с учётом предпочтений, Reasons - почему матч поднят для пользователя
+type PersonalMatch struct {
+	Match     Match    `json:"match"`
+	Relevance float64  `json:"relevance"`
+	Reasons   []string `json:"reasons,omitempty"`
+}
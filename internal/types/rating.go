@@ -0,0 +1,62 @@
+package types
+
+import "time"
+
+// Структура для хранения разбора рейтинга матча: из чего сложилось итоговое число
+// Сохраняется вместе с матчем, чтобы бот мог объяснить, почему матч попал в топ
+type RatingBreakdown struct {
+	Strategy        string `json:"strategy" bson:"strategy"`
+	StrategyVersion string `json:"strategyVersion" bson:"strategyversion"`
+	Profile         string `json:"profile" bson:"profile"`
+	ProfileVersion  int    `json:"profileVersion" bson:"profileversion"`
+
+	HomeStrength float64 `json:"homeStrength" bson:"homestrength"`
+	AwayStrength float64 `json:"awayStrength" bson:"awaystrength"`
+	LeagueWeight float64 `json:"leagueWeight" bson:"leagueweight"`
+	HomeForm     float64 `json:"homeForm" bson:"homeform"`
+	AwayForm     float64 `json:"awayForm" bson:"awayform"`
+	FormFactor   float64 `json:"formFactor" bson:"formfactor"`
+	// Последние результаты команд, например "WWDLW"
+	HomeLastFive string `json:"homeLastFive,omitempty" bson:"homelastfive,omitempty"`
+	AwayLastFive string `json:"awayLastFive,omitempty" bson:"awaylastfive,omitempty"`
+	// Зрелищность команд по последним матчам, от 0 до 1
+	HomeEntertainment float64 `json:"homeEntertainment,omitempty" bson:"homeentertainment,omitempty"`
+	AwayEntertainment float64 `json:"awayEntertainment,omitempty" bson:"awayentertainment,omitempty"`
+	// Рейтинги Эло команд перед матчем; заполняются стратегиями, которые их используют
+	HomeElo float64 `json:"homeElo,omitempty" bson:"homeelo,omitempty"`
+	AwayElo float64 `json:"awayElo,omitempty" bson:"awayelo,omitempty"`
+	// Насколько равны соперники: 1 - силы совпадают, 0 - максимальный разрыв
+	Closeness float64 `json:"closeness" bson:"closeness"`
+	// Прогноз модели Пуассона; заполняется стратегиями, которые его используют
+	Prediction *Prediction `json:"prediction,omitempty" bson:"prediction,omitempty"`
+
+	// Вклад каждой части в базовый рейтинг (значение, умноженное на вес профиля)
+	StrengthPart float64 `json:"strengthPart" bson:"strengthpart"`
+	LeaguePart   float64 `json:"leaguePart" bson:"leaguepart"`
+	FormPart     float64 `json:"formPart" bson:"formpart"`
+	BaseRating   float64 `json:"baseRating" bson:"baserating"`
+
+	DerbyBonus float64 `json:"derbyBonus" bson:"derbybonus"`
+	StageBonus float64 `json:"stageBonus" bson:"stagebonus"`
+	// Счёт первого матча для ответных матчей кубков, например "2:1" с точки зрения хозяев
+	FirstLegScore    string  `json:"firstLegScore,omitempty" bson:"firstlegscore,omitempty"`
+	CrossLeagueBonus float64 `json:"crossLeagueBonus" bson:"crossleaguebonus"`
+	// Турнирная значимость матча от 0 до 1 и бонус за неё
+	Stakes      float64 `json:"stakes" bson:"stakes"`
+	StakesBonus float64 `json:"stakesBonus" bson:"stakesbonus"`
+	// Бонус за зрелищность пары
+	EntertainmentBonus float64 `json:"entertainmentBonus" bson:"entertainmentbonus"`
+
+	Rating float64 `json:"rating" bson:"rating"`
+}
+
+// Точка истории рейтинга матча: какое значение получил матч и почему его пересчитали
+// Пишется при каждом изменении рейтинга, чтобы было видно, как он двигался до начала матча
+type RatingPoint struct {
+	MatchID         int       `json:"matchId" bson:"matchid"`
+	Rating          float64   `json:"rating" bson:"rating"`
+	Strategy        string    `json:"strategy" bson:"strategy"`
+	StrategyVersion string    `json:"strategyVersion" bson:"strategyversion"`
+	Reason          string    `json:"reason" bson:"reason"`
+	ComputedAt      time.Time `json:"computedAt" bson:"computedat"`
+}
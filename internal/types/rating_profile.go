@@ -0,0 +1,162 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Структура для хранения профиля рейтинга матчей
+// Профиль загружается из JSON-файла и содержит все веса и бонусы,
+// которые раньше были зашиты в код. Version увеличивается при каждом изменении весов,
+// а изменения без новой версии всё равно видны по Checksum
+type RatingProfile struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+
+	Weights struct {
+		Position float64 `json:"position"`
+		League   float64 `json:"league"`
+		Form     float64 `json:"form"`
+	} `json:"weights"`
+
+	CrossLeagueBonus float64 `json:"crossLeagueBonus"`
+	MinRating        float64 `json:"minRating"`
+	MaxRating        float64 `json:"maxRating"`
+
+	// Максимальный бонус за турнирную значимость матча (борьба за титул, еврокубки, выживание)
+	StakesBonus float64 `json:"stakesBonus"`
+	// Максимальный бонус за зрелищность пары (много голов, обе забивают, поздние развязки)
+	EntertainmentBonus float64 `json:"entertainmentBonus"`
+
+	// Вес лиги по ключу из types.Leagues
+	LeagueNorm map[string]float64 `json:"leagueNorm"`
+	// Бонус за стадию кубкового турнира: код соревнования football-data -> Match.Stage -> бонус
+	StageWeights map[string]map[string]float64 `json:"stageWeights"`
+}
+
+// Validate проверяет, что профиль можно использовать для расчёта рейтинга
+func (p *RatingProfile) Validate() error {
+	var errs []error
+	if p.Name == "" {
+		errs = append(errs, errors.New("name is empty"))
+	}
+	if p.Version < 1 {
+		errs = append(errs, fmt.Errorf("version must be positive, got %d", p.Version))
+	}
+	for name, w := range map[string]float64{"position": p.Weights.Position, "league": p.Weights.League, "form": p.Weights.Form} {
+		if w < 0 || w > 1 {
+			errs = append(errs, fmt.Errorf("weight %s must be in [0, 1], got %v", name, w))
+		}
+	}
+	if p.Weights.Position+p.Weights.League+p.Weights.Form <= 0 {
+		errs = append(errs, errors.New("at least one weight must be positive"))
+	}
+	if p.CrossLeagueBonus < 0 || p.CrossLeagueBonus > 1 {
+		errs = append(errs, fmt.Errorf("crossLeagueBonus must be in [0, 1], got %v", p.CrossLeagueBonus))
+	}
+	if p.StakesBonus < 0 || p.StakesBonus > 1 {
+		errs = append(errs, fmt.Errorf("stakesBonus must be in [0, 1], got %v", p.StakesBonus))
+	}
+	if p.EntertainmentBonus < 0 || p.EntertainmentBonus > 1 {
+		errs = append(errs, fmt.Errorf("entertainmentBonus must be in [0, 1], got %v", p.EntertainmentBonus))
+	}
+	if p.MinRating < 0 || p.MaxRating > 1 || p.MinRating >= p.MaxRating {
+		errs = append(errs, fmt.Errorf("rating bounds must satisfy 0 <= min < max <= 1, got [%v, %v]", p.MinRating, p.MaxRating))
+	}
+	if len(p.LeagueNorm) == 0 {
+		errs = append(errs, errors.New("leagueNorm is empty"))
+	}
+	for league, w := range p.LeagueNorm {
+		if w < 0 || w > 1 {
+			errs = append(errs, fmt.Errorf("leagueNorm[%s] must be in [0, 1], got %v", league, w))
+		}
+	}
+	for competition, stages := range p.StageWeights {
+		for stage, b := range stages {
+			if b < 0 || b > 1 {
+				errs = append(errs, fmt.Errorf("stageWeights[%s][%s] must be in [0, 1], got %v", competition, stage, b))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Checksum возвращает короткий хэш содержимого профиля
+// Входит в версию стратегии, чтобы изменённые веса пересчитывались, даже если version забыли увеличить
+func (p RatingProfile) Checksum() string {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:4])
+}
+
+// DefaultRatingProfile возвращает профиль с весами, которые использовались до выноса в файл
+// Используется, если путь к файлу профиля не задан
+func DefaultRatingProfile() RatingProfile {
+	p := RatingProfile{
+		Name:               "default",
+		Version:            6,
+		CrossLeagueBonus:   0.15,
+		StakesBonus:        0.5, // Слабее не перевешивает низкие места участников матча за выживание
+		EntertainmentBonus: 0.1,
+		MinRating:          0.1, // Минимальный рейтинг, чтобы избежать нулей
+		MaxRating:          1.0,
+		LeagueNorm: map[string]float64{
+			"ChampionsLeague": 1.0,
+			"EuropaLeague":    0.8,
+			"PremierLeague":   0.9,
+			"LaLiga":          0.8,
+			"SerieA":          0.8,
+			"Bundesliga":      0.75,
+			"Ligue1":          0.7,
+		},
+		StageWeights: map[string]map[string]float64{
+			// Лига чемпионов
+			"CL": {
+				"PLAYOFFS":       0.25, // 1/16
+				"LAST_16":        0.5,  // 1/8
+				"QUARTER_FINALS": 0.75, // 1/4
+				"SEMI_FINALS":    0.9,  // 1/2
+				"FINAL":          1.0,  // Final
+			},
+			// Лига Европы
+			"EL": {
+				"PLAYOFFS":       0.15,
+				"LAST_16":        0.3,
+				"QUARTER_FINALS": 0.45,
+				"SEMI_FINALS":    0.6,
+				"FINAL":          0.8,
+			},
+			// Кубок Англии
+			"FAC": {
+				"LAST_16":        0.1,
+				"QUARTER_FINALS": 0.2,
+				"SEMI_FINALS":    0.35,
+				"FINAL":          0.6,
+			},
+			// Кубок Германии
+			"DFB": {
+				"LAST_16":        0.1,
+				"QUARTER_FINALS": 0.2,
+				"SEMI_FINALS":    0.35,
+				"FINAL":          0.6,
+			},
+			// Кубок Испании
+			"CDR": {
+				"LAST_16":        0.1,
+				"QUARTER_FINALS": 0.2,
+				"SEMI_FINALS":    0.35,
+				"FINAL":          0.6,
+			},
+		},
+	}
+	p.Weights.Position = 0.15
+	p.Weights.League = 0.35
+	p.Weights.Form = 0.15
+	return p
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Структура для хранения дерби (принципиального противостояния) двух команд
+// Команды хранятся по ID football-data в порядке TeamA < TeamB, поэтому пара уникальна
+type Rivalry struct {
+	TeamA     int    `json:"teamA" bson:"teama"`
+	TeamB     int    `json:"teamB" bson:"teamb"`
+	TeamAName string `json:"teamAName" bson:"teamaname"`
+	TeamBName string `json:"teamBName" bson:"teambname"`
+	// Накал противостояния от 0 до 1; используется как бонус к рейтингу матча
+	Intensity float64   `json:"intensity" bson:"intensity"`
+	Notes     string    `json:"notes,omitempty" bson:"notes,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedat"`
+}
+
+// RivalryPair возвращает ID команд в порядке хранения
+func RivalryPair(first, second int) (int, int) {
+	if first > second {
+		return second, first
+	}
+	return first, second
+}
+
+// Validate проверяет, что дерби можно сохранить
+func (r *Rivalry) Validate() error {
+	if r.TeamA == 0 || r.TeamB == 0 || r.TeamA == r.TeamB {
+		return fmt.Errorf("rivalry must name two different teams, got %d and %d", r.TeamA, r.TeamB)
+	}
+	if r.TeamA > r.TeamB {
+		return fmt.Errorf("rivalry teams must be ordered, got %d and %d", r.TeamA, r.TeamB)
+	}
+	if r.Intensity < 0 || r.Intensity > 1 {
+		return fmt.Errorf("rivalry intensity must be in [0, 1], got %v", r.Intensity)
+	}
+	return nil
+}
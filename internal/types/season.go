@@ -0,0 +1,42 @@
+package types
+
+import (
+	"strconv"
+	"time"
+)
+
+// Текущий сезон соревнования по данным /competitions/{code}
+type Season struct {
+	// Ключ лиги в Leagues, например "PremierLeague"
+	League string `json:"league" bson:"league"`
+	Code   string `json:"code" bson:"code"`
+	ID     int    `json:"id" bson:"id"`
+	// Даты начала и конца сезона в формате 2006-01-02
+	StartDate       string    `json:"startDate" bson:"startdate"`
+	EndDate         string    `json:"endDate" bson:"enddate"`
+	CurrentMatchday int       `json:"currentMatchday" bson:"currentmatchday"`
+	UpdatedAt       time.Time `json:"updatedAt" bson:"updatedat"`
+}
+
+// Year возвращает год начала сезона, как его понимает параметр season в API; 0 - даты нет
+func (s Season) Year() int {
+	if len(s.StartDate) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(s.StartDate[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
+
+// Cтруктура для декодинга Json-файла из API
+type CompetitionResponse struct {
+	Code          string `json:"code"`
+	CurrentSeason struct {
+		ID              int    `json:"id"`
+		StartDate       string `json:"startDate"`
+		EndDate         string `json:"endDate"`
+		CurrentMatchday int    `json:"currentMatchday"`
+	} `json:"currentSeason"`
+}
@@ -0,0 +1,27 @@
+package types
+
+// На чём основано ожидание перед матчем
+const (
+	// Вероятности исходов по модели Пуассона
+	UpsetBasisModel = "model"
+	// Места команд в таблице
+	UpsetBasisTable = "table"
+)
+
+// Структура для хранения сенсации: сыгранного матча, исход которого противоречил ожиданиям
+// Surprise от 0 до 1: чем меньше шансов было у случившегося исхода, тем больше
+type Upset struct {
+	Match Match  `json:"match"`
+	Basis string `json:"basis"`
+	// Вероятность случившегося исхода по модели; для ожиданий по таблице - оценка по разрыву в местах
+	ResultProbability float64 `json:"resultProbability"`
+	HomePosition      int     `json:"homePosition,omitempty"`
+	AwayPosition      int     `json:"awayPosition,omitempty"`
+	Surprise          float64 `json:"surprise"`
+}
+
+// Уведомление о сенсации и подписчики лиги, которым его нужно отправить
+type UpsetAlert struct {
+	Upset      Upset
+	Recipients []int64
+}
@@ -1,10 +1,13 @@
 package types
 
+import "time"
+
 type User struct {
-	ID         int
-	TitleName  string
-	TelegramID int64
-	Username   string
-	FirstName  string
-	LastName   string
+	ID         int       `json:"id"`
+	TitleName  string    `json:"-"`
+	TelegramID int64     `json:"telegram_id"`
+	Username   string    `json:"username"`
+	FirstName  string    `json:"-"`
+	LastName   string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
 }
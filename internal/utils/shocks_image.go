@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+
+	"github.com/fogleman/gg"
+	"github.com/vsespontanno/tgbot_fschedule/internal/types"
+)
+
+// ShocksImage создает изображение с главными сенсациями недели
+// Оформление такое же, как у расписания; высота зависит от числа сенсаций
+// Возвращает буфер с изображением или ошибку, если что-то пошло не так
+func ShocksImage(upsets []types.Upset) (*bytes.Buffer, error) {
+	const (
+		width        = 780
+		padding      = 10
+		headerHeight = 60
+		fontSize     = 20
+		lineWidth    = 1.5
+		rowHeight    = 60
+	)
+
+	var (
+		backgroundColor   = color.RGBA{18, 18, 18, 255}
+		textColor         = color.RGBA{230, 230, 230, 255}
+		headerTextColor   = color.RGBA{255, 255, 255, 255}
+		headerBgColor     = color.RGBA{40, 40, 40, 255}
+		alternateRowColor = color.RGBA{30, 30, 30, 255}
+		lineColor         = color.RGBA{60, 60, 60, 255}
+	)
+	if len(upsets) == 0 {
+		return nil, fmt.Errorf("no upsets data provided")
+	}
+	height := headerHeight + rowHeight*(len(upsets)+1) + padding
+
+	dc := gg.NewContext(width, height)
+	dc.SetColor(backgroundColor)
+	dc.Clear()
+
+	if err := dc.LoadFontFace("assets/NotoSans-Regular.ttf", fontSize); err != nil {
+		return nil, fmt.Errorf("error loading font: %v", err)
+	}
+
+	dc.SetColor(headerTextColor)
+	dc.DrawStringAnchored("Главные сенсации недели", float64(width/2), float64(padding)+20, 0.5, 0.5)
+
+	// Колонки: дата, матч, счёт и шансы случившегося исхода до матча
+	headers := []string{"Дата", "Матч", "Счёт", "Шансы"}
+	colWidths := []int{120, 430, 100, 110}
+
+	y := headerHeight + padding
+
+	dc.SetColor(headerBgColor)
+	dc.DrawRectangle(0, float64(headerHeight)-10, float64(width), float64(rowHeight)-5)
+	dc.Fill()
+
+	dc.SetColor(lineColor)
+	dc.SetLineWidth(lineWidth)
+	dc.DrawLine(0, float64(headerHeight+rowHeight-15), float64(width), float64(headerHeight+rowHeight-15))
+	dc.Stroke()
+
+	dc.SetColor(textColor)
+	currentX := padding
+	for i, header := range headers {
+		dc.DrawStringAnchored(header, float64(currentX+colWidths[i]/2), float64(y)+5, 0.5, 0.5)
+		currentX += colWidths[i]
+		if i < len(headers)-1 {
+			dc.SetColor(lineColor)
+			dc.DrawLine(float64(currentX), float64(headerHeight), float64(currentX), float64(height))
+			dc.Stroke()
+			dc.SetColor(textColor)
+		}
+	}
+
+	y += rowHeight
+	for i, upset := range upsets {
+		match := upset.Match
+		if i%2 == 1 {
+			dc.SetColor(alternateRowColor)
+			dc.DrawRectangle(0, float64(y-rowHeight/2), float64(width), float64(rowHeight))
+			dc.Fill()
+		}
+		dc.SetColor(lineColor)
+		dc.DrawLine(0, float64(y+rowHeight/2), float64(width), float64(y+rowHeight/2))
+		dc.Stroke()
+
+		dc.SetColor(textColor)
+		cells := []string{
+			match.UTCDate[0:10],
+			fmt.Sprintf("%s - %s", match.HomeTeam.Name, match.AwayTeam.Name),
+			fmt.Sprintf("%d:%d", match.Score.FullTime.Home, match.Score.FullTime.Away),
+			fmt.Sprintf("%.0f%%", upset.ResultProbability*100),
+		}
+		currentX = padding
+		for j, cell := range cells {
+			if j == 1 {
+				// Длинные названия команд переносятся на следующую строку
+				maxWidth := float64(colWidths[j]) - padding*2
+				dc.DrawStringWrapped(cell, float64(currentX+colWidths[j]/2), float64(y), 0.5, 0.5, maxWidth, 1.1, gg.AlignCenter)
+			} else {
+				dc.DrawStringAnchored(cell, float64(currentX+colWidths[j]/2), float64(y), 0.5, 0.5)
+			}
+			currentX += colWidths[j]
+		}
+		y += rowHeight
+	}
+
+	buf := new(bytes.Buffer)
+	if err := dc.EncodePNG(buf); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf, nil
+}